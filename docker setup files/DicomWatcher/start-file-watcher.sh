@@ -32,7 +32,7 @@ if [ "$1" = "--services" ]; then
     # Setup filebrowser
     filebrowser config init -d "$DB" > /dev/null 2>&1
     filebrowser config set -d "$DB" -a 0.0.0.0 -p "$PORT" -r "/" --minimumPasswordLength 4 > /dev/null 2>&1
-    filebrowser users add admin runpod -d "$DB" --perm.admin > /dev/null 2>&1
+    filebrowser users add admin "${SLICER_PASSWORD:-runpod}" -d "$DB" --perm.admin > /dev/null 2>&1
 
     echo "========================================"
     echo "  SERVICE LOGS (verbose)"
@@ -101,7 +101,7 @@ echo "  ║           DICOM WATCHER + AI SEGMENTATION              ║"
 echo "  ╠════════════════════════════════════════════════════════╣"
 echo "  ║                                                        ║"
 echo "  ║  File Transfer:  $URL"
-echo "  ║  Login:          admin / runpod                        ║"
+printf "  ║  Login:          admin / %-29s║\n" "${SLICER_PASSWORD:-runpod}"
 echo "  ║                                                        ║"
 echo "  ║  Upload a DICOM folder via browser.                    ║"
 echo "  ║  All series auto-load into 3D Slicer.                  ║"
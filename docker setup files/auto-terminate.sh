@@ -0,0 +1,72 @@
+#!/bin/bash
+# Copyright (c) 2025-2026 Mik Gangal
+# Licensed under CC BY-NC-SA 4.0 - https://creativecommons.org/licenses/by-nc-sa/4.0/
+#
+# Pod-side auto-termination safety net
+# The launcher sets these environment variables when it creates the pod:
+#   SLICER_MAX_LIFETIME_MIN       - terminate after this many minutes (0 = no limit)
+#   SLICER_HEARTBEAT_TIMEOUT_MIN  - terminate when heartbeats stop for this long (0 = off)
+#   SLICER_HEARTBEAT_FILE         - file the launcher rewrites via the File Browser API
+#   SLICER_HEARTBEAT_TOKEN        - heartbeat must start with this token
+#   SLICER_DEADLINE               - terminate at this unix time (fleets; 0 = none)
+# The heartbeat file contains "<token> <deadline-unix-time>"; a non-zero deadline
+# replaces the max lifetime so the launcher can extend the session.
+
+MAX_MIN=${SLICER_MAX_LIFETIME_MIN:-0}
+TIMEOUT_MIN=${SLICER_HEARTBEAT_TIMEOUT_MIN:-0}
+HB_FILE=${SLICER_HEARTBEAT_FILE:-/tmp/slicer-heartbeat}
+TOKEN=${SLICER_HEARTBEAT_TOKEN:-}
+END_AT=${SLICER_DEADLINE:-0}
+CHECK_INTERVAL=60
+
+if [ "$MAX_MIN" -eq 0 ] && [ "$TIMEOUT_MIN" -eq 0 ] && [ "$END_AT" -eq 0 ]; then
+    echo "Auto-termination not configured (pod not started by the launcher)"
+    exit 0
+fi
+
+START=$(date +%s)
+DEADLINE=0
+[ "$MAX_MIN" -gt 0 ] && DEADLINE=$((START + MAX_MIN * 60))
+# A fixed end time (e.g. the end of a class) wins when it comes first
+if [ "$END_AT" -gt 0 ] && { [ "$DEADLINE" -eq 0 ] || [ "$END_AT" -lt "$DEADLINE" ]; }; then
+    DEADLINE=$END_AT
+fi
+
+terminate_pod() {
+    echo "$(date '+%Y-%m-%d %H:%M:%S') $1 - terminating pod $RUNPOD_POD_ID"
+    sync
+
+    # runpodctl and a pod-scoped RUNPOD_API_KEY are provided by RunPod
+    if command -v runpodctl > /dev/null 2>&1 && runpodctl remove pod "$RUNPOD_POD_ID"; then
+        exit 0
+    fi
+    if [ -n "$RUNPOD_API_KEY" ]; then
+        curl -fsS -X DELETE -H "Authorization: Bearer $RUNPOD_API_KEY" \
+            "https://rest.runpod.io/v1/pods/$RUNPOD_POD_ID" && exit 0
+    fi
+    echo "Could not terminate pod - will retry"
+}
+
+echo "Auto-termination active: max lifetime ${MAX_MIN}m, heartbeat timeout ${TIMEOUT_MIN}m"
+
+while sleep "$CHECK_INTERVAL"; do
+    NOW=$(date +%s)
+
+    # Heartbeats count from container start until the first one arrives
+    LAST_BEAT=$START
+    if [ -f "$HB_FILE" ]; then
+        read -r HB_TOKEN HB_DEADLINE < "$HB_FILE"
+        if [ -n "$TOKEN" ] && [ "$HB_TOKEN" = "$TOKEN" ]; then
+            LAST_BEAT=$(stat -c %Y "$HB_FILE")
+            if [ -n "$HB_DEADLINE" ] && [ "$HB_DEADLINE" -gt 0 ] 2>/dev/null; then
+                DEADLINE=$HB_DEADLINE
+            fi
+        fi
+    fi
+
+    if [ "$DEADLINE" -gt 0 ] && [ "$NOW" -ge "$DEADLINE" ]; then
+        terminate_pod "Max lifetime reached"
+    elif [ "$TIMEOUT_MIN" -gt 0 ] && [ $((NOW - LAST_BEAT)) -ge $((TIMEOUT_MIN * 60)) ]; then
+        terminate_pod "No launcher heartbeat for $(( (NOW - LAST_BEAT) / 60 ))m"
+    fi
+done
@@ -0,0 +1,112 @@
+# Copyright (c) 2025-2026 Mik Gangal
+# Licensed under CC BY-NC-SA 4.0 - https://creativecommons.org/licenses/by-nc-sa/4.0/
+"""
+Headless Batch Segmentation Script
+Run by batch-segment (the launcher's batch mode) without a main window:
+loads a DICOM study, thresholds it into a bone segment and exports it
+like "Create 3D Models" does, to /FILE TRANSFERS/Export_<name>_<timestamp>/.
+
+Usage: Slicer --no-splash --no-main-window --python-script batch-segment.py <study dir> <name> [threshold HU]
+"""
+
+import os
+import sys
+import slicer
+from DICOMLib import DICOMUtils
+
+DEFAULT_THRESHOLD = 300  # HU, cortical and most trabecular bone
+SMOOTHING_MM = 1.0
+
+
+def load_study(study_dir):
+    """Import a DICOM folder and return the largest scalar volume in it"""
+    db_dir = os.path.join(os.path.dirname(study_dir.rstrip("/")), ".dicomdb")
+    os.makedirs(db_dir, exist_ok=True)
+    with DICOMUtils.TemporaryDICOMDatabase(db_dir) as db:
+        DICOMUtils.importDicom(study_dir, db)
+        for patient in db.patients():
+            DICOMUtils.loadPatientByUID(patient)
+
+    volumes = slicer.util.getNodesByClass("vtkMRMLScalarVolumeNode")
+    if not volumes:
+        raise RuntimeError(f"no image volume could be loaded from {study_dir}")
+
+    def voxels(node):
+        dims = node.GetImageData().GetDimensions() if node.GetImageData() else (0, 0, 0)
+        return dims[0] * dims[1] * dims[2]
+
+    return max(volumes, key=voxels)
+
+
+def segment_bone(volume, threshold):
+    """Threshold the volume, keep the largest island and smooth it"""
+    seg_node = slicer.mrmlScene.AddNewNodeByClass("vtkMRMLSegmentationNode", "Segmentation")
+    seg_node.CreateDefaultDisplayNodes()
+    seg_node.SetReferenceImageGeometryParameterFromVolumeNode(volume)
+    segment_id = seg_node.GetSegmentation().AddEmptySegment("Bone")
+
+    editor_widget = slicer.qMRMLSegmentEditorWidget()
+    editor_widget.setMRMLScene(slicer.mrmlScene)
+    editor_node = slicer.mrmlScene.AddNewNodeByClass("vtkMRMLSegmentEditorNode")
+    editor_widget.setMRMLSegmentEditorNode(editor_node)
+    editor_widget.setSegmentationNode(seg_node)
+    editor_widget.setSourceVolumeNode(volume)
+    editor_node.SetSelectedSegmentID(segment_id)
+
+    editor_widget.setActiveEffectByName("Threshold")
+    effect = editor_widget.activeEffect()
+    effect.setParameter("MinimumThreshold", str(threshold))
+    effect.setParameter("MaximumThreshold", str(volume.GetImageData().GetScalarRange()[1]))
+    effect.self().onApply()
+
+    editor_widget.setActiveEffectByName("Islands")
+    effect = editor_widget.activeEffect()
+    effect.setParameter("Operation", "KEEP_LARGEST_ISLAND")
+    effect.self().onApply()
+
+    editor_widget.setActiveEffectByName("Smoothing")
+    effect = editor_widget.activeEffect()
+    effect.setParameter("SmoothingMethod", "MEDIAN")
+    effect.setParameter("KernelSizeMm", str(SMOOTHING_MM))
+    effect.self().onApply()
+
+    editor_widget.setActiveEffectByName(None)
+    slicer.mrmlScene.RemoveNode(editor_node)
+
+    seg_node.CreateClosedSurfaceRepresentation()
+    return seg_node
+
+
+def main(argv):
+    if len(argv) < 2:
+        print("Usage: batch-segment.py <study dir> <name> [threshold HU]")
+        return 2
+    study_dir, name = argv[0], argv[1]
+    threshold = float(argv[2]) if len(argv) > 2 else DEFAULT_THRESHOLD
+
+    # export_all_segments() comes from the export script; its trigger polling
+    # is stopped so a "Create 3D Models" click isn't claimed by this instance
+    exec(open("/usr/local/share/slicer/export-segments.py").read(), globals())
+    timer = getattr(slicer, "exportPollingTimer", None)
+    if timer:
+        timer.stop()
+
+    print(f"Loading {study_dir}")
+    volume = load_study(study_dir)
+    print(f"Segmenting {volume.GetName()} at {threshold:g} HU")
+    segment_bone(volume, threshold)
+
+    if export_all_segments(name) == 0:  # noqa: F821 (defined by the exec above)
+        print("Nothing was exported")
+        return 1
+    return 0
+
+
+try:
+    code = main(sys.argv[1:])
+except Exception as e:
+    import traceback
+    traceback.print_exc()
+    print(f"Batch segmentation failed: {e}")
+    code = 1
+sys.exit(code)
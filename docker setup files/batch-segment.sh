@@ -0,0 +1,42 @@
+#!/bin/bash
+# Copyright (c) 2025-2026 Mik Gangal
+# Licensed under CC BY-NC-SA 4.0 - https://creativecommons.org/licenses/by-nc-sa/4.0/
+#
+# Headless segmentation (installed as /usr/local/bin/batch-segment)
+# Started over SSH by the launcher's batch mode for each uploaded study.
+# Results land in /FILE TRANSFERS/Export_<name>_<timestamp>/ like a manual
+# "Create 3D Models"; a failed run leaves /root/batch/<name>.failed instead.
+#
+# Usage: batch-segment <study dir> <name> [threshold HU]
+
+STUDY_DIR="$1"
+NAME="$2"
+THRESHOLD="${3:-300}"
+BATCH_DIR="/root/batch"
+LOG="$BATCH_DIR/$NAME.log"
+
+if [ -z "$STUDY_DIR" ] || [ -z "$NAME" ]; then
+    echo "Usage: batch-segment <study dir> <name> [threshold HU]" >&2
+    exit 2
+fi
+
+mkdir -p "$BATCH_DIR"
+rm -f "$BATCH_DIR/$NAME.failed"
+
+if [ ! -d "$STUDY_DIR" ]; then
+    echo "Study folder not found: $STUDY_DIR" > "$BATCH_DIR/$NAME.failed"
+    exit 1
+fi
+
+# Slicer needs an X display even without a main window; the VNC desktop provides one
+export DISPLAY="${DISPLAY:-:1}"
+
+/usr/local/bin/Slicer --no-splash --no-main-window \
+    --python-script /usr/local/share/slicer/batch-segment.py \
+    "$STUDY_DIR" "$NAME" "$THRESHOLD" > "$LOG" 2>&1
+STATUS=$?
+
+if [ $STATUS -ne 0 ]; then
+    { echo "Slicer exited with status $STATUS"; tail -n 20 "$LOG"; } > "$BATCH_DIR/$NAME.failed"
+fi
+exit $STATUS
@@ -22,12 +22,17 @@ POLL_INTERVAL_MS = 1000  # Check every second
 SKIP_SEGMENTS = {'<bg>', '<fg>', 'background', 'foreground'}
 
 
-def export_all_segments():
-    """Export all segments from all segmentation nodes as STL files + combined OBJ"""
+def export_all_segments(export_name=None):
+    """Export all segments from all segmentation nodes as STL files + combined OBJ.
+    export_name goes into the folder name (batch runs name it after the study,
+    launches with --case pass the case ID as SLICER_CASE)."""
+
+    export_name = export_name or os.environ.get("SLICER_CASE")
 
     # Create timestamped export folder
     timestamp = datetime.now().strftime("%Y%m%d_%H%M%S")
-    export_dir = os.path.join(EXPORT_BASE, f"Export_{timestamp}")
+    folder = f"Export_{export_name}_{timestamp}" if export_name else f"Export_{timestamp}"
+    export_dir = os.path.join(EXPORT_BASE, folder)
     os.makedirs(export_dir, exist_ok=True)
 
     exported_count = 0
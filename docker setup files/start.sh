@@ -8,10 +8,23 @@ echo "=== Starting 3D Slicer + nnInteractive Environment ==="
 # Model weights are pre-downloaded to /root/.nninteractive_weights
 # The server will find them when running from /root (default home directory)
 
+# Per-pod password: the launcher's fleet mode sets SLICER_PASSWORD so every
+# seat of a class gets its own; single sessions keep the defaults
+POD_PASSWORD=${SLICER_PASSWORD:-runpod}
+VNC_PASSWORD=${SLICER_PASSWORD:-vncpass}
+
 # Configure SSH (optional)
-echo "root:runpod" | chpasswd
+echo "root:$POD_PASSWORD" | chpasswd
 sed -i 's/^#*PermitRootLogin.*/PermitRootLogin yes/' /etc/ssh/sshd_config
 sed -i 's/^#*PasswordAuthentication.*/PasswordAuthentication yes/' /etc/ssh/sshd_config
+
+# The launcher's batch mode passes a throwaway public key so it can start
+# segmentation jobs over SSH without the password
+if [ -n "$SLICER_SSH_KEY" ]; then
+    mkdir -p /root/.ssh && chmod 700 /root/.ssh
+    echo "$SLICER_SSH_KEY" >> /root/.ssh/authorized_keys
+    chmod 600 /root/.ssh/authorized_keys
+fi
 service ssh start || true
 
 # Configure VirtualGL
@@ -47,6 +60,13 @@ export MESA_LOADER_DRIVER_OVERRIDE=swrast
 export __EGL_VENDOR_LIBRARY_FILENAMES=/usr/share/glvnd/egl_vendor.d/50_mesa.json
 export GALLIUM_DRIVER=llvmpipe
 
+# With a per-pod password, noVNC asks for it instead of auto-logging in
+if [ -n "$SLICER_PASSWORD" ]; then
+    printf '%s\n%s\nn\n' "$VNC_PASSWORD" "$VNC_PASSWORD" | /opt/TurboVNC/bin/vncpasswd /root/.vnc/passwd > /dev/null
+    chmod 600 /root/.vnc/passwd
+    sed -i 's/password=vncpass&//' /usr/share/novnc/index.html
+fi
+
 RESOLUTION=${VNC_RESOLUTION:-1920x1080}
 /opt/TurboVNC/bin/vncserver :1 -geometry $RESOLUTION -depth 24 -xstartup /root/.vnc/xstartup
 
@@ -57,10 +77,10 @@ websockify -D --web=/usr/share/novnc 6080 localhost:5901
 echo ""
 echo "=== Environment Ready ==="
 echo ""
-echo "TurboVNC:       port 5901 (password: vncpass)"
+echo "TurboVNC:       port 5901 (password: $VNC_PASSWORD)"
 echo "                Direct TCP: Use RunPod's exposed TCP port"
 echo "noVNC:          port 6080 (browser: auto-login via HTTP proxy)"
-echo "SSH:            port 22 (root / runpod)"
+echo "SSH:            port 22 (root / $POD_PASSWORD)"
 echo "File Transfer:  port 8080 (start from desktop icon)"
 echo "nnInteractive:  port 8000 (start from desktop icon)"
 echo ""
@@ -69,5 +89,9 @@ echo ""
 echo "For best performance, use TurboVNC client with direct TCP connection."
 echo ""
 
+# Pod-side safety net: terminates the pod when the launcher's heartbeats stop
+# or the max lifetime is reached (configured via env vars set by the launcher)
+nohup /usr/local/bin/auto-terminate >> /tmp/auto-terminate.log 2>&1 &
+
 # Keep container running
 tail -f /dev/null
@@ -0,0 +1,430 @@
+// Copyright (c) 2025-2026 Mik Gangal
+// Licensed under CC BY-NC-SA 4.0 - https://creativecommons.org/licenses/by-nc-sa/4.0/
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// API keys are saved per RunPod account (e.g. "grants" and "teaching") in
+// accounts.json in the state directory. --account picks one, a profile's
+// "account" binds it to one, and the default account is used otherwise.
+// The single key of older versions (~/.slicer-launcher-config) is read as
+// the account "default" until accounts.json exists.
+
+const (
+	accountsFileName   = "accounts.json"
+	defaultAccountName = "default"
+	defaultMinHours    = 1.0
+)
+
+// accountNamePattern keeps account names short and easy to type
+var accountNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]{0,31}$`)
+
+// selectedAccount is the --account given on the command line, if any
+var selectedAccount string
+
+// Credentials is what accounts.json holds
+type Credentials struct {
+	Default  string            `json:"default"`
+	Accounts map[string]string `json:"accounts"` // API key by account name
+}
+
+func validAccountName(name string) error {
+	if !accountNamePattern.MatchString(name) {
+		return fmt.Errorf("account name %q must be 1-32 letters, digits, '.', '_' or '-'", name)
+	}
+	return nil
+}
+
+func credentialsPath() (string, error) {
+	dir, err := getStateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, accountsFileName), nil
+}
+
+// loadCredentials reads accounts.json, or the legacy single key
+func loadCredentials() (*Credentials, error) {
+	creds := &Credentials{Accounts: make(map[string]string)}
+	path, err := credentialsPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err == nil {
+		if err := json.Unmarshal(data, creds); err != nil {
+			return nil, fmt.Errorf("could not parse %s: %w", accountsFileName, err)
+		}
+		if creds.Accounts == nil {
+			creds.Accounts = make(map[string]string)
+		}
+		return creds, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("could not read %s: %w", accountsFileName, err)
+	}
+
+	// Carry over the key saved by older versions
+	legacyPath, err := getConfigPath()
+	if err != nil {
+		return creds, nil
+	}
+	if data, err := os.ReadFile(legacyPath); err == nil {
+		if key := strings.TrimSpace(string(data)); key != "" {
+			creds.Accounts[defaultAccountName] = key
+			creds.Default = defaultAccountName
+		}
+	}
+	return creds, nil
+}
+
+func (c *Credentials) save() error {
+	path, err := credentialsPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// names returns the account names in order
+func (c *Credentials) names() []string {
+	names := make([]string, 0, len(c.Accounts))
+	for name := range c.Accounts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// set saves a key under name; the first account becomes the default
+func (c *Credentials) set(name, key string) {
+	c.Accounts[name] = key
+	if c.Default == "" || c.Accounts[c.Default] == "" {
+		c.Default = name
+	}
+}
+
+// lookup returns the account to use and its key ("" if none is saved).
+// An empty name means the default account.
+func (c *Credentials) lookup(name string) (string, string) {
+	if name == "" {
+		name = c.Default
+		if name == "" && len(c.Accounts) == 1 {
+			name = c.names()[0]
+		}
+	}
+	return name, c.Accounts[name]
+}
+
+// addSecrets redacts every saved key from the logs
+func (c *Credentials) addSecrets() {
+	for _, key := range c.Accounts {
+		addSecret(key)
+	}
+}
+
+// launchAccount returns the account a profile launches on: --account, or
+// the one the profile is bound to ("" = the default account)
+func launchAccount(p Profile) (string, error) {
+	if selectedAccount != "" && p.Account != "" && selectedAccount != p.Account {
+		return "", fmt.Errorf("profile %q is bound to account %q, not %q", p.Name, p.Account, selectedAccount)
+	}
+	if selectedAccount != "" {
+		return selectedAccount, nil
+	}
+	return p.Account, nil
+}
+
+// accountAPIKey returns the saved key of an account, or fallback for ""
+func accountAPIKey(account, fallback string) (string, error) {
+	if account == "" {
+		return fallback, nil
+	}
+	creds, err := loadCredentials()
+	if err != nil {
+		return "", err
+	}
+	key := creds.Accounts[account]
+	if key == "" {
+		return "", fmt.Errorf("no API key saved for account %q (add it with 'slicer-launcher accounts add %s')", account, account)
+	}
+	addSecret(key)
+	return key, nil
+}
+
+// keyAccount returns the saved account an API key belongs to, or "" for a
+// key that isn't saved (e.g. from the environment)
+func keyAccount(apiKey string) string {
+	creds, err := loadCredentials()
+	if err != nil || apiKey == "" {
+		return ""
+	}
+	for _, name := range creds.names() {
+		if creds.Accounts[name] == apiKey {
+			return name
+		}
+	}
+	return ""
+}
+
+// profileAPIKey returns the account and key a subcommand launches the
+// profile with, given the key commandSetup picked
+func profileAPIKey(p Profile, fallback string) (string, string, error) {
+	account, err := launchAccount(p)
+	if err != nil {
+		return "", "", err
+	}
+	key, err := accountAPIKey(account, fallback)
+	return account, key, err
+}
+
+// takeAccountFlag removes a leading --account NAME so it also works in
+// front of subcommands ("slicer-launcher --account teaching list")
+func takeAccountFlag(args []string) []string {
+	if len(args) < 2 {
+		return args
+	}
+	arg := args[1]
+	for _, prefix := range []string{"--account=", "-account="} {
+		if strings.HasPrefix(arg, prefix) {
+			selectedAccount = strings.TrimPrefix(arg, prefix)
+			return append(args[:1:1], args[2:]...)
+		}
+	}
+	if (arg == "--account" || arg == "-account") && len(args) > 2 {
+		selectedAccount = args[2]
+		return append(args[:1:1], args[3:]...)
+	}
+	return args
+}
+
+// minHours is the runtime the balance must cover before a launch
+func (p Profile) minHours() float64 {
+	if p.MinHours == 0 {
+		return defaultMinHours
+	}
+	return p.MinHours
+}
+
+// checkBalance refuses a launch of pods pods when the account can't pay
+// for the profile's minimum runtime on its most expensive GPU type. It
+// returns the account info, or nil if RunPod could not be asked.
+func checkBalance(apiKey string, p Profile, pods int) (*AccountInfo, error) {
+	info, err := getAccountInfo(apiKey)
+	if err != nil {
+		slog.Warn("balance check skipped", "error", err)
+		return nil, nil
+	}
+	hours := p.minHours()
+	if hours < 0 {
+		return info, nil
+	}
+	rate := p.BidPerGPU
+	if !p.Spot || rate == 0 {
+		if rate, err = maxGPUPrice(apiKey, p.GPUTypes); err != nil {
+			slog.Warn("balance check skipped", "error", err)
+			return info, nil
+		}
+	}
+	need := rate * hours * float64(pods)
+	if info.Balance < need {
+		return info, fmt.Errorf("the balance of $%.2f is below the $%.2f needed for %.1f h of %d pod(s) at $%.2f/hr (profile %q, minHours) - top up the account or pick another with --account",
+			info.Balance, need, hours, pods, rate, p.Name)
+	}
+	return info, nil
+}
+
+// accountLabel names an account in messages
+func accountLabel(account string) string {
+	if account == "" {
+		return defaultAccountName
+	}
+	return account
+}
+
+// runAccountsCommand is the accounts subcommand: named API keys
+func runAccountsCommand(args []string) int {
+	if len(args) == 0 {
+		args = []string{"list"}
+	}
+	switch args[0] {
+	case "list":
+		return runAccountsList()
+	case "add":
+		return runAccountsAdd(args[1:])
+	case "remove":
+		return runAccountsRemove(args[1:])
+	case "default":
+		return runAccountsDefault(args[1:])
+	}
+	fmt.Println("usage: slicer-launcher accounts [list] | add <name> | remove <name> | default <name>")
+	return exitUsage
+}
+
+func runAccountsList() int {
+	creds, err := loadCredentials()
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitError
+	}
+	creds.addSecrets()
+	if len(creds.Accounts) == 0 {
+		fmt.Println("No API keys saved. Add one with 'slicer-launcher accounts add <name>'.")
+		return exitOK
+	}
+	settings, err := loadSettings()
+	if err != nil {
+		settings = &Settings{}
+	}
+
+	fmt.Printf("%s  %-16s %-28s %10s %10s  %s%s\n", colorDim, "Account", "Email", "Balance", "Spend/hr", "Profiles", colorReset)
+	var problems []string
+	for _, name := range creds.names() {
+		marker := " "
+		if name == creds.Default {
+			marker = "*"
+		}
+		email, balance, spend := "?", "?", "?"
+		check, err := checkAPIKey(creds.Accounts[name])
+		if check != nil {
+			email, balance, spend = check.Email, fmt.Sprintf("$%.2f", check.Balance), fmt.Sprintf("$%.2f", check.CostPerHr)
+		}
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", name, err))
+		}
+		var profiles []string
+		for pname, p := range settings.Profiles {
+			if p.Account == name {
+				profiles = append(profiles, pname)
+			}
+		}
+		sort.Strings(profiles)
+		fmt.Printf("%s %-16s %-28s %10s %10s  %s\n", marker, name, truncate(email, 28), balance, spend, strings.Join(profiles, ", "))
+	}
+	for _, p := range problems {
+		fmt.Printf("%s%s%s\n", colorYellow, p, colorReset)
+	}
+	fmt.Printf("%s* default account; pick another with --account <name>%s\n", colorDim, colorReset)
+	return exitOK
+}
+
+func runAccountsAdd(args []string) int {
+	if len(args) != 1 {
+		fmt.Println("usage: slicer-launcher accounts add <name>")
+		return exitUsage
+	}
+	name := args[0]
+	if err := validAccountName(name); err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitUsage
+	}
+	creds, err := loadCredentials()
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitError
+	}
+	if creds.Accounts[name] != "" && !confirm(fmt.Sprintf("Replace the saved key of account %q? (y/n): ", name)) {
+		return exitOK
+	}
+
+	fmt.Printf("Paste the API key for %s: ", name)
+	key, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	key = strings.TrimSpace(key)
+	if key == "" {
+		fmt.Printf("%sError: API key cannot be empty%s\n", colorRed, colorReset)
+		return exitUsage
+	}
+	addSecret(key)
+
+	// Only keys that can launch are worth saving
+	check, err := checkNewAPIKey(key)
+	switch {
+	case keyProblem(err):
+		fmt.Printf("%sError: not saved - %v%s\n", colorRed, err, colorReset)
+		return exitError
+	case err != nil:
+		fmt.Printf("%sWarning: could not check the key: %v%s\n", colorYellow, err, colorReset)
+	default:
+		fmt.Println(describeKey(name, check))
+	}
+
+	creds.set(name, key)
+	if err := creds.save(); err != nil {
+		fmt.Printf("%sError: could not save the key: %v%s\n", colorRed, err, colorReset)
+		return exitError
+	}
+	fmt.Printf("%s✓%s Saved the key of account %s\n", colorGreen, colorReset, name)
+	return exitOK
+}
+
+func runAccountsRemove(args []string) int {
+	if len(args) != 1 {
+		fmt.Println("usage: slicer-launcher accounts remove <name>")
+		return exitUsage
+	}
+	creds, err := loadCredentials()
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitError
+	}
+	name := args[0]
+	if _, ok := creds.Accounts[name]; !ok {
+		fmt.Printf("%sError: no account %q%s\n", colorRed, name, colorReset)
+		return exitUsage
+	}
+	delete(creds.Accounts, name)
+	if creds.Default == name {
+		creds.Default = ""
+		if names := creds.names(); len(names) > 0 {
+			creds.Default = names[0]
+		}
+	}
+	if err := creds.save(); err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitError
+	}
+	fmt.Printf("%s✓%s Removed account %s\n", colorGreen, colorReset, name)
+	if creds.Default != "" {
+		fmt.Printf("  The default account is %s\n", creds.Default)
+	}
+	return exitOK
+}
+
+func runAccountsDefault(args []string) int {
+	if len(args) != 1 {
+		fmt.Println("usage: slicer-launcher accounts default <name>")
+		return exitUsage
+	}
+	creds, err := loadCredentials()
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitError
+	}
+	name := args[0]
+	if creds.Accounts[name] == "" {
+		fmt.Printf("%sError: no account %q%s\n", colorRed, name, colorReset)
+		return exitUsage
+	}
+	creds.Default = name
+	if err := creds.save(); err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitError
+	}
+	fmt.Printf("%s✓%s %s is now the default account\n", colorGreen, colorReset, name)
+	return exitOK
+}
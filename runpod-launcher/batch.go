@@ -0,0 +1,692 @@
+// Copyright (c) 2025-2026 Mik Gangal
+// Licensed under CC BY-NC-SA 4.0 - https://creativecommons.org/licenses/by-nc-sa/4.0/
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Batch mode: a pod without browsers or a desktop user works through a
+// queue of DICOM studies. Each study is uploaded, segmented on the pod by
+// batch-segment (started over SSH with a throwaway key) and its Export_*
+// folder downloaded. The pod is terminated when the queue is empty or the
+// cost ceiling is reached.
+
+const (
+	// batchDir is where studies are uploaded on the pod. It is outside
+	// /FILE TRANSFERS so the DICOM watcher doesn't open them in a desktop Slicer.
+	batchDir = "/root/batch"
+
+	batchPodName         = "slicer-batch"
+	batchPollInterval    = 10 * time.Second
+	batchSSHAttempts     = 6
+	defaultBatchMaxCost  = 10.0
+	defaultStudyTimeout  = 30 * time.Minute
+	defaultBoneThreshold = 300
+	batchReportName      = "batch-report.json"
+)
+
+// Study results
+const (
+	studyDone    = "done"
+	studyFailed  = "failed"
+	studySkipped = "skipped"
+)
+
+// batchNameUnsafe matches what can't go into a study name; names end up in
+// pod paths, folder names and the remote command line
+var batchNameUnsafe = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// BatchStudy is one queued study and how it went
+type BatchStudy struct {
+	Name    string   `json:"name"`
+	Source  string   `json:"source"`
+	Files   int      `json:"files"`
+	Bytes   int64    `json:"bytes"`
+	Status  string   `json:"status"`
+	Result  string   `json:"result,omitempty"`
+	Error   string   `json:"error,omitempty"`
+	Seconds float64  `json:"seconds,omitempty"`
+	Repair  []string `json:"repair,omitempty"` // meshes to fix before printing
+
+	files []string // relative paths, forward slashes
+}
+
+// BatchReport is written next to the results when the run ends
+type BatchReport struct {
+	Profile   string       `json:"profile"`
+	PodID     string       `json:"podId,omitempty"`
+	GPU       string       `json:"gpu,omitempty"`
+	CostPerHr float64      `json:"costPerHr,omitempty"`
+	MaxCost   float64      `json:"maxCost"`
+	Cost      float64      `json:"cost"`
+	Started   time.Time    `json:"started"`
+	Finished  time.Time    `json:"finished"`
+	Studies   []BatchStudy `json:"studies"`
+}
+
+func (r *BatchReport) count(status string) int {
+	n := 0
+	for _, s := range r.Studies {
+		if s.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// batchRun is a running batch: the pod and what is needed to reach it
+type batchRun struct {
+	apiKey    string
+	pod       *PodResponse
+	created   time.Time
+	replaced  float64 // what the pods launchReady gave up on cost
+	ssh       PortInfo
+	keyFile   string
+	fb        *fileBrowserClient
+	spot      bool
+	maxCost   float64
+	threshold float64
+	timeout   time.Duration
+	outDir    string
+}
+
+// cost is what the run's pods have cost so far
+func (b *batchRun) cost() float64 {
+	return b.replaced + b.pod.CostPerHr*time.Since(b.created).Hours()
+}
+
+func (b *batchRun) overBudget() bool {
+	return b.cost() >= b.maxCost
+}
+
+func runBatchCommand(args []string) int {
+	fs := flag.NewFlagSet("batch", flag.ContinueOnError)
+	profileName := fs.String("profile", defaultProfileName, "settings profile the pod is created from")
+	queue := fs.String("queue", "", "folder whose subfolders are the studies to process")
+	outDir := fs.String("o", "batch-results", "folder the results and the report are written to")
+	maxCost := fs.Float64("max-cost", defaultBatchMaxCost, "most the run may cost in USD; remaining studies are skipped")
+	threshold := fs.Float64("threshold", defaultBoneThreshold, "bone threshold in HU")
+	studyTimeout := fs.Duration("study-timeout", defaultStudyTimeout, "give up on a study after this long")
+	launchTimeout := fs.Duration("launch-timeout", defaultLaunchTimeout, "give up if the pod isn't ready after this long")
+	yes := fs.Bool("yes", false, "start without asking for confirmation")
+	fs.Usage = func() {
+		fmt.Println("Usage: slicer-launcher batch [flags] STUDY_FOLDER... | -queue FOLDER")
+		fs.PrintDefaults()
+	}
+	dirs, err := parseArgs(fs, args)
+	if err != nil {
+		return exitUsage
+	}
+
+	switch {
+	case *maxCost <= 0 || *studyTimeout <= 0 || *launchTimeout <= 0:
+		fmt.Println("-max-cost, -study-timeout and -launch-timeout must be positive")
+		return exitUsage
+	case len(dirs) == 0 && *queue == "":
+		fs.Usage()
+		return exitUsage
+	}
+
+	if *queue != "" {
+		entries, err := os.ReadDir(*queue)
+		if err != nil {
+			fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+			return exitUsage
+		}
+		for _, e := range entries {
+			if e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+				dirs = append(dirs, filepath.Join(*queue, e.Name()))
+			}
+		}
+	}
+	studies, err := collectStudies(dirs)
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitUsage
+	}
+
+	// Jobs are started over SSH with a key that only lives for this run
+	for _, tool := range []string{"ssh", "ssh-keygen"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			fmt.Printf("%sError: %s not found - batch mode needs OpenSSH installed%s\n", colorRed, tool, colorReset)
+			return exitUsage
+		}
+	}
+
+	apiKey, err := commandSetup()
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitUsage
+	}
+	profile, err := appSettings.profile(*profileName)
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitUsage
+	}
+	account, apiKey, err := profileAPIKey(profile, apiKey)
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitUsage
+	}
+	if err := prepareVolume(apiKey, &profile); err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitUsage
+	}
+	info, err := checkBalance(apiKey, profile, 1)
+	if err != nil {
+		fmt.Printf("%sError: account %s: %v%s\n", colorRed, accountLabel(account), err, colorReset)
+		return exitLaunchFailed
+	}
+
+	// The ceiling also caps the pod's own lifetime, worked out from the
+	// most expensive GPU type it might get, so it holds without us
+	rate, err := maxGPUPrice(apiKey, profile.GPUTypes)
+	if err != nil {
+		fmt.Printf("%sError: could not check GPU prices for the cost ceiling: %v%s\n", colorRed, err, colorReset)
+		return exitError
+	}
+	lifetime := time.Duration(*maxCost / rate * float64(time.Hour))
+	if max := time.Duration(defaultMaxHours) * time.Hour; lifetime > max {
+		lifetime = max
+	}
+	if lifetime < time.Minute {
+		fmt.Printf("%sError: $%.2f buys less than a minute at $%.2f/hr%s\n", colorRed, *maxCost, rate, colorReset)
+		return exitUsage
+	}
+
+	var total int64
+	for _, s := range studies {
+		total += s.Bytes
+	}
+	fmt.Printf("Batch: %d studies (%s) from profile %q\n", len(studies), formatBytes(total), profile.Name)
+	fmt.Printf("  Ceiling: $%.2f = %s at up to $%.2f/hr - the pod terminates itself after that\n", *maxCost, formatDuration(lifetime), rate)
+	fmt.Printf("  Results: %s\n", *outDir)
+	if info != nil {
+		fmt.Printf("  Account: %s, balance $%.2f\n", accountLabel(account), info.Balance)
+	}
+	if !*yes && !confirm("Start? (y/n): ") {
+		return exitOK
+	}
+	if err := os.MkdirAll(*outDir, 0755); err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitError
+	}
+
+	closeLog, err := initLogging(false)
+	if err != nil {
+		logEvent(levelWarn, "Session log disabled: %v", err)
+	}
+	atExit(closeLog)
+	subscribe((&slogSink{}).handle)
+	subscribe(newBatchPrinter().handle)
+	setupSignalHandler()
+
+	keyDir, err := os.MkdirTemp("", "slicer-batch-")
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitError
+	}
+	atExit(func() { os.RemoveAll(keyDir) })
+	keyFile := filepath.Join(keyDir, "id_ed25519")
+	if out, err := exec.Command("ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-C", batchPodName, "-f", keyFile).CombinedOutput(); err != nil {
+		fmt.Printf("%sError: could not create an SSH key: %v %s%s\n", colorRed, err, strings.TrimSpace(string(out)), colorReset)
+		return exitError
+	}
+	publicKey, err := os.ReadFile(keyFile + ".pub")
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitError
+	}
+
+	guard, err := newPodGuard(lifetime, defaultHeartbeatTimeout)
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitError
+	}
+
+	report := &BatchReport{Profile: profile.Name, MaxCost: *maxCost, Started: time.Now(), Studies: studies}
+	launchStart = report.Started
+	limits := launchLimits{queue: defaultQueueTimeout, pull: defaultPullTimeout, deadline: launchStart.Add(*launchTimeout)}
+	var (
+		created  time.Time
+		podRate  float64
+		replaced float64
+	)
+	pod, ports, err := launchReady(apiKey, batchPodName, profile, limits,
+		func() (map[string]string, error) {
+			env := guard.env()
+			env["SLICER_SSH_KEY"] = strings.TrimSpace(string(publicKey))
+			return env, nil
+		},
+		func(pod *PodResponse) {
+			// The previous pod, stuck or interrupted, was terminated just now
+			if !created.IsZero() {
+				replaced += podRate * time.Since(created).Hours()
+			}
+			podRate = pod.CostPerHr
+			activeAPIKey, activePodID, created = apiKey, pod.ID, time.Now()
+			if err := recordPod(PodRecord{ID: pod.ID, CreatedAt: created, Status: podStatusActive, Account: keyAccount(apiKey)}); err != nil {
+				logEvent(levelWarn, "Could not record pod in launcher state: %v", err)
+			}
+		})
+	if err != nil {
+		activePodID = ""
+		logEvent(levelError, "%v", err)
+		showLogHint()
+		return exitLaunchFailed
+	}
+	report.PodID, report.GPU, report.CostPerHr = pod.ID, pod.Machine.GpuDisplayName, pod.CostPerHr
+	guard.deadline = launchStart.Add(guard.maxLifetime)
+
+	b := &batchRun{
+		apiKey: apiKey, pod: pod, created: created, replaced: replaced, keyFile: keyFile,
+		fb: newFileBrowserClient(pod.ID), spot: profile.Spot,
+		maxCost: *maxCost, threshold: *threshold, timeout: *studyTimeout, outDir: *outDir,
+	}
+	code := exitOK
+	ssh, ok := ports[22]
+	switch {
+	case !ok:
+		logEvent(levelError, "Pod %s has no public SSH port - the template must expose port 22", pod.ID)
+		code = exitNotReady
+	case !waitForFileBrowser(podURL(pod.ID, fileBrowserPort)):
+		logEvent(levelError, "File Browser did not start on pod %s", pod.ID)
+		code = exitNotReady
+	}
+	if code != exitOK {
+		for i := range studies {
+			studies[i].Status, studies[i].Error = studySkipped, "pod not usable"
+		}
+	} else {
+		b.ssh = ssh
+		stop := make(chan struct{})
+		startPodHeartbeat(guard, b.fb, stop)
+		b.process(studies)
+		close(stop)
+	}
+
+	report.Cost = b.cost()
+	report.Finished = time.Now()
+	fmt.Println()
+	printBatchReport(report)
+	if err := writeBatchReport(report, *outDir); err != nil {
+		logEvent(levelError, "Could not write the report: %v", err)
+	}
+
+	if end := endSession(apiKey, pod.ID); end != exitOK {
+		return end
+	}
+	activePodID = ""
+	if code == exitOK && report.count(studyDone) < len(studies) {
+		code = exitError
+	}
+	return code
+}
+
+// collectStudies turns study folders into queue entries with unique names
+func collectStudies(dirs []string) ([]BatchStudy, error) {
+	var studies []BatchStudy
+	used := make(map[string]int)
+	for _, dir := range dirs {
+		info, err := os.Stat(dir)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("%s is not a folder", dir)
+		}
+
+		s := BatchStudy{Source: dir, Status: studySkipped}
+		err = filepath.Walk(dir, func(p string, fi os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if fi.IsDir() || strings.HasPrefix(fi.Name(), ".") {
+				return nil
+			}
+			rel, err := filepath.Rel(dir, p)
+			if err != nil {
+				return err
+			}
+			s.files = append(s.files, filepath.ToSlash(rel))
+			s.Files++
+			s.Bytes += fi.Size()
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if s.Files == 0 {
+			return nil, fmt.Errorf("%s has no files", dir)
+		}
+
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return nil, err
+		}
+		name := strings.Trim(batchNameUnsafe.ReplaceAllString(filepath.Base(abs), "_"), "_")
+		if name == "" {
+			name = "study"
+		}
+		used[name]++
+		if n := used[name]; n > 1 {
+			name = fmt.Sprintf("%s-%d", name, n)
+		}
+		s.Name = name
+		studies = append(studies, s)
+	}
+	return studies, nil
+}
+
+// process works through the queue. Once the ceiling is reached or the pod
+// is gone, the remaining studies are skipped.
+func (b *batchRun) process(studies []BatchStudy) {
+	stopReason := ""
+	for i := range studies {
+		s := &studies[i]
+		if stopReason == "" && b.overBudget() {
+			stopReason = fmt.Sprintf("cost ceiling of $%.2f reached", b.maxCost)
+		}
+		if stopReason != "" {
+			s.Status, s.Error = studySkipped, stopReason
+			continue
+		}
+
+		fmt.Printf("\n%s[%d/%d]%s %s (%d files, %s)\n", colorCyan, i+1, len(studies), colorReset, s.Name, s.Files, formatBytes(s.Bytes))
+		start := time.Now()
+		result, err := b.runStudy(s)
+		s.Seconds = time.Since(start).Seconds()
+		if err != nil {
+			s.Status, s.Error = studyFailed, err.Error()
+			fmt.Printf("  %s✗ %v%s\n", colorRed, err, colorReset)
+			if halt, ok := err.(*batchHalt); ok {
+				stopReason = halt.reason
+			}
+			continue
+		}
+		s.Status, s.Result = studyDone, result
+	}
+}
+
+// batchHalt fails a study in a way that ends the whole queue
+type batchHalt struct {
+	reason string
+}
+
+func (h *batchHalt) Error() string {
+	return h.reason
+}
+
+// runStudy uploads one study, runs the segmentation and downloads the
+// export. It returns where the result was saved.
+func (b *batchRun) runStudy(s *BatchStudy) (string, error) {
+	podDir := batchDir + "/" + s.Name
+	for _, rel := range s.files {
+		if err := b.uploadFile(filepath.Join(s.Source, filepath.FromSlash(rel)), podDir+"/"+rel); err != nil {
+			return "", fmt.Errorf("upload failed: %w", err)
+		}
+	}
+	fmt.Printf("  %s✓%s Uploaded\n", colorGreen, colorReset)
+
+	// Exports of an earlier run with the same name (on a network volume)
+	// must not be mistaken for this one
+	earlier := make(map[string]bool)
+	exports, _ := b.exports(s.Name)
+	for _, e := range exports {
+		earlier[e] = true
+	}
+	b.fb.remove(batchDir + "/" + s.Name + ".failed")
+
+	job := fmt.Sprintf("nohup batch-segment '%s' '%s' %g > /dev/null 2>&1 &", podDir, s.Name, b.threshold)
+	if err := b.remote(job); err != nil {
+		return "", fmt.Errorf("could not start segmentation: %w", err)
+	}
+	fmt.Printf("  Segmenting at %g HU...\n", b.threshold)
+
+	deadline := time.Now().Add(b.timeout)
+	for {
+		time.Sleep(batchPollInterval)
+
+		if exports, err := b.exports(s.Name); err == nil {
+			for _, e := range exports {
+				if earlier[e] || !b.exportComplete(e) {
+					continue
+				}
+				result := filepath.Join(b.outDir, e+".zip")
+				if err := archiveExport(b.fb, transferDir+"/"+e, result); err != nil {
+					return "", fmt.Errorf("download failed: %w", err)
+				}
+				s.Repair = meshRepairs(result)
+				return result, nil
+			}
+		}
+		if reason, failed := b.failure(s.Name); failed {
+			b.saveLog(s.Name)
+			return "", fmt.Errorf("segmentation failed: %s", reason)
+		}
+
+		if b.spot {
+			if status, err := getPodStatus(b.apiKey, b.pod.ID); err == nil && (status == "" || status == "EXITED") {
+				podInterrupted(&interruptedError{podID: b.pod.ID})
+				return "", &batchHalt{reason: "pod was interrupted by RunPod"}
+			}
+		}
+		switch {
+		case b.overBudget():
+			b.kill(s.Name)
+			return "", &batchHalt{reason: fmt.Sprintf("cost ceiling of $%.2f reached", b.maxCost)}
+		case time.Now().After(deadline):
+			b.kill(s.Name)
+			b.saveLog(s.Name)
+			return "", fmt.Errorf("no result after %s", formatDuration(b.timeout))
+		}
+	}
+}
+
+func (b *batchRun) uploadFile(local, podPath string) error {
+	f, err := os.Open(local)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	return b.fb.upload(podPath, f, info.Size())
+}
+
+// exports lists the Export_<name>_<timestamp> folders of a study
+func (b *batchRun) exports(name string) ([]string, error) {
+	items, err := b.fb.list(transferDir)
+	if err != nil {
+		return nil, err
+	}
+	pattern := regexp.MustCompile(`^Export_` + regexp.QuoteMeta(name) + `_\d{8}_\d{6}$`)
+	var names []string
+	for _, item := range items {
+		if item.IsDir && pattern.MatchString(item.Name) {
+			names = append(names, item.Name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// exportComplete reports whether Slicer is done with an export folder; the
+// summary is written last
+func (b *batchRun) exportComplete(export string) bool {
+	items, err := b.fb.list(transferDir + "/" + export)
+	if err != nil {
+		return false
+	}
+	for _, item := range items {
+		if item.Name == "EXPORT_SUMMARY.txt" {
+			return true
+		}
+	}
+	return false
+}
+
+// failure returns the last line batch-segment left in <name>.failed
+func (b *batchRun) failure(name string) (string, bool) {
+	body, _, err := b.fb.download(batchDir + "/" + name + ".failed")
+	if err != nil {
+		return "", false
+	}
+	defer body.Close()
+	data, _ := io.ReadAll(body)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	return strings.TrimSpace(lines[len(lines)-1]), true
+}
+
+// saveLog keeps Slicer's output of a failed study next to the results
+func (b *batchRun) saveLog(name string) {
+	body, _, err := b.fb.download(batchDir + "/" + name + ".log")
+	if err != nil {
+		return
+	}
+	defer body.Close()
+	dest := filepath.Join(b.outDir, name+".log")
+	f, err := os.Create(dest)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	if _, err := io.Copy(f, body); err == nil {
+		fmt.Printf("  Slicer log: %s\n", dest)
+	}
+}
+
+// kill stops a study's Slicer; best effort, the pod is terminated anyway
+func (b *batchRun) kill(name string) {
+	if err := b.remote(fmt.Sprintf("pkill -f 'batch-segment.py %s/%s '", batchDir, name)); err != nil {
+		logEvent(levelWarn, "Could not stop the segmentation of %s: %v", name, err)
+	}
+}
+
+// remote runs a command on the pod over SSH. sshd may still be starting
+// right after the pod is ready, so connection failures are retried.
+func (b *batchRun) remote(command string) error {
+	args := []string{
+		"-i", b.keyFile,
+		"-o", "StrictHostKeyChecking=no",
+		"-o", "UserKnownHostsFile=" + os.DevNull,
+		"-o", "BatchMode=yes",
+		"-o", "ConnectTimeout=15",
+		"-o", "LogLevel=ERROR",
+		"-p", fmt.Sprint(b.ssh.PublicPort),
+		"root@" + b.ssh.IP,
+		command,
+	}
+	var err error
+	for attempt := 1; attempt <= batchSSHAttempts; attempt++ {
+		var out []byte
+		out, err = exec.Command("ssh", args...).CombinedOutput()
+		if err == nil {
+			return nil
+		}
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			err = fmt.Errorf("%v: %s", err, msg)
+		}
+		// ssh exits with 255 when it could not connect; anything else came from the command
+		if exit, ok := err.(*exec.ExitError); ok && exit.ExitCode() != 255 {
+			return err
+		}
+		time.Sleep(batchPollInterval)
+	}
+	return err
+}
+
+// batchPrinter shows the pod's launch progress as one line per phase
+type batchPrinter struct {
+	phase string
+}
+
+func newBatchPrinter() *batchPrinter {
+	return &batchPrinter{}
+}
+
+func (p *batchPrinter) handle(ev Event) {
+	at := formatDuration(ev.Elapsed())
+	switch ev.Type {
+	case evCreated:
+		fmt.Printf("  %s✓%s Pod %s (%s, $%.2f/hr)\n", colorGreen, colorReset, ev.PodID, ev.GPU, ev.CostPerHr)
+	case evProgress:
+		if ev.Phase == p.phase {
+			return
+		}
+		p.phase = ev.Phase
+		fmt.Printf("  %s%s - %s%s\n", colorDim, ev.Phase, at, colorReset)
+	case evPhaseDone:
+		if ev.Phase == phaseDesktopReady {
+			fmt.Printf("  %s✓ Pod ready%s - %s\n", colorGreen, colorReset, at)
+		}
+	case evRetry:
+		fmt.Printf("  %s↻ %s%s\n", colorYellow, ev.Message, colorReset)
+	case evInterrupted:
+		fmt.Printf("  %s%s%s\n", colorYellow, ev.Message, colorReset)
+	case evTerminated:
+		fmt.Printf("%s✓%s Pod %s terminated\n", colorGreen, colorReset, ev.PodID)
+	case evLog:
+		switch ev.Level {
+		case levelWarn:
+			fmt.Printf("%sWarning: %s%s\n", colorYellow, ev.Message, colorReset)
+		case levelError:
+			fmt.Printf("%sError: %s%s\n", colorRed, ev.Message, colorReset)
+		}
+	}
+}
+
+// printBatchReport shows how every study went
+func printBatchReport(r *BatchReport) {
+	fmt.Printf("  %s%-24s %-8s %-8s %s%s\n", colorDim, "Study", "Result", "Time", "Output", colorReset)
+	for _, s := range r.Studies {
+		color, detail := colorReset, s.Result
+		switch s.Status {
+		case studyDone:
+			color = colorGreen
+			if len(s.Repair) > 0 {
+				detail += fmt.Sprintf(" %s(%d meshes need repair)%s", colorYellow, len(s.Repair), colorReset)
+			}
+		case studyFailed:
+			color, detail = colorRed, s.Error
+		case studySkipped:
+			color, detail = colorYellow, s.Error
+		}
+		took := "-"
+		if s.Seconds > 0 {
+			took = formatDuration(time.Duration(s.Seconds * float64(time.Second)))
+		}
+		fmt.Printf("  %-24s %s%-8s%s %-8s %s\n", s.Name, color, s.Status, colorReset, took, detail)
+	}
+	fmt.Println()
+	fmt.Printf("%d done, %d failed, %d skipped │ %s$%.2f%s of $%.2f │ %s\n",
+		r.count(studyDone), r.count(studyFailed), r.count(studySkipped),
+		colorRed, r.Cost, colorReset, r.MaxCost, formatDuration(r.Finished.Sub(r.Started)))
+}
+
+func writeBatchReport(r *BatchReport, dir string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	dest := filepath.Join(dir, batchReportName)
+	if err := os.WriteFile(dest, append(data, '\n'), 0644); err != nil {
+		return err
+	}
+	fmt.Printf("Report: %s\n", dest)
+	return nil
+}
@@ -0,0 +1,303 @@
+// Copyright (c) 2025-2026 Mik Gangal
+// Licensed under CC BY-NC-SA 4.0 - https://creativecommons.org/licenses/by-nc-sa/4.0/
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A launch can carry a case ID (--case) and free-text notes. The case goes
+// into the pod name, the launch history, the session log and the names of
+// downloaded exports; the pod's cost is appended to costs.jsonl when it
+// goes away. The costs subcommand adds them up per case.
+
+const (
+	costsFileName = "costs.jsonl"
+	caseEnv       = "SLICER_CASE" // export-segments.py names exports after it
+)
+
+// caseIDPattern keeps case IDs usable in pod, file and folder names
+var caseIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]{0,47}$`)
+
+// casePodName matches launch pod names that carry a case ID
+var casePodName = regexp.MustCompile(`^slicer-(.+)-\d{10}$`)
+
+// activeCase is the case ID of the current launch, if any
+var activeCase string
+
+func validCaseID(id string) error {
+	if !caseIDPattern.MatchString(id) {
+		return fmt.Errorf("case ID %q must be 1-48 letters, digits, '.', '_' or '-' and start with a letter or digit", id)
+	}
+	return nil
+}
+
+// launchPodName names a launch's pod: slicer-<unix>, or slicer-<case>-<unix>
+func launchPodName(caseID string) string {
+	if caseID == "" {
+		return fmt.Sprintf("slicer-%d", time.Now().Unix())
+	}
+	return fmt.Sprintf("slicer-%s-%d", caseID, time.Now().Unix())
+}
+
+// caseFromPodName returns the case ID in a launch pod's name, or ""
+func caseFromPodName(name string) string {
+	if m := casePodName.FindStringSubmatch(name); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// caseExportName tags an Export_<timestamp> folder name with the case
+// unless the pod already did (Export_<case>_<timestamp>)
+func caseExportName(caseID, name string) string {
+	if caseID == "" || strings.HasPrefix(name, "Export_"+caseID+"_") {
+		return name
+	}
+	return "Export_" + caseID + "_" + strings.TrimPrefix(name, "Export_")
+}
+
+// promptNotes asks for optional notes about the case at launch
+func promptNotes(caseID string) string {
+	fmt.Printf("Notes for case %s (optional, Enter to skip): ", caseID)
+	notes, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	return strings.TrimSpace(notes)
+}
+
+// CostEntry is one line of costs.jsonl: what one pod cost
+type CostEntry struct {
+	PodID     string    `json:"podId"`
+	Case      string    `json:"case,omitempty"`
+	Notes     string    `json:"notes,omitempty"`
+	Profile   string    `json:"profile"`
+	GPU       string    `json:"gpu"`
+	Created   time.Time `json:"created"`
+	Ended     time.Time `json:"ended"`
+	CostPerHr float64   `json:"costPerHr"`
+	Cost      float64   `json:"cost"`
+	Running   bool      `json:"running,omitempty"` // the launcher exited and left the pod running; cost so far
+}
+
+// costLedger records the cost of every pod a launch creates
+type costLedger struct {
+	mu    sync.Mutex
+	base  CostEntry
+	entry *CostEntry
+}
+
+func newCostLedger(profile, caseID, notes string) *costLedger {
+	return &costLedger{base: CostEntry{Profile: profile, Case: caseID, Notes: notes}}
+}
+
+func (l *costLedger) handle(ev Event) {
+	switch ev.Type {
+	case evCreated:
+		l.close(false)
+		l.mu.Lock()
+		entry := l.base
+		entry.PodID, entry.GPU, entry.CostPerHr, entry.Created = ev.PodID, ev.GPU, ev.CostPerHr, ev.Time
+		l.entry = &entry
+		l.mu.Unlock()
+	case evTerminated, evStopped:
+		l.mu.Lock()
+		ours := l.entry != nil && l.entry.PodID == ev.PodID
+		l.mu.Unlock()
+		if ours {
+			l.close(false)
+		}
+	}
+}
+
+// save records a pod the launcher leaves running when it exits
+func (l *costLedger) save() {
+	l.close(true)
+}
+
+func (l *costLedger) close(running bool) {
+	l.mu.Lock()
+	entry := l.entry
+	l.entry = nil
+	l.mu.Unlock()
+	if entry == nil {
+		return
+	}
+	entry.Ended = time.Now()
+	entry.Running = running
+	entry.Cost = entry.CostPerHr * entry.Ended.Sub(entry.Created).Hours()
+	if err := appendCostEntry(*entry); err != nil {
+		logEvent(levelWarn, "Could not record the session cost: %v", err)
+	}
+}
+
+func costsPath() (string, error) {
+	dir, err := getStateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, costsFileName), nil
+}
+
+func appendCostEntry(e CostEntry) error {
+	path, err := costsPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// loadCostEntries reads costs.jsonl, skipping lines it can't parse
+func loadCostEntries() ([]CostEntry, error) {
+	path, err := costsPath()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []CostEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e CostEntry
+		if json.Unmarshal(scanner.Bytes(), &e) == nil {
+			entries = append(entries, e)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// runCosts is the costs subcommand: pod costs per case
+func runCosts(args []string) int {
+	fs := flag.NewFlagSet("costs", flag.ContinueOnError)
+	caseID := fs.String("case", "", "list the pods of this case")
+	days := fs.Int("days", 0, "only pods from the last N days (0 = all)")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	entries, err := loadCostEntries()
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitError
+	}
+	var selected []CostEntry
+	for _, e := range entries {
+		if *days > 0 && time.Since(e.Created) > time.Duration(*days)*24*time.Hour {
+			continue
+		}
+		if *caseID != "" && e.Case != *caseID {
+			continue
+		}
+		selected = append(selected, e)
+	}
+	if len(selected) == 0 {
+		fmt.Println("No pod costs recorded yet. They are saved to " + costsFileName + " when a pod goes away.")
+		return exitOK
+	}
+
+	if *caseID != "" {
+		printCostEntries(selected)
+		return exitOK
+	}
+	printCaseTotals(selected)
+	return exitOK
+}
+
+func printCostEntries(entries []CostEntry) {
+	var total float64
+	fmt.Printf("%s%-16s %-14s %-24s %9s %9s%s\n", colorDim, "Started", "Pod", "GPU", "Runtime", "Cost", colorReset)
+	for _, e := range entries {
+		runtime := formatDuration(e.Ended.Sub(e.Created).Round(time.Minute))
+		if e.Running {
+			runtime += "+"
+		}
+		fmt.Printf("%-16s %-14s %-24s %9s %9s\n", e.Created.Local().Format("2006-01-02 15:04"),
+			e.PodID, truncate(e.GPU, 24), runtime, fmt.Sprintf("$%.2f", e.Cost))
+		if e.Notes != "" {
+			fmt.Printf("  %s%s%s\n", colorDim, e.Notes, colorReset)
+		}
+		total += e.Cost
+	}
+	fmt.Printf("Total: $%.2f over %d pod(s)\n", total, len(entries))
+	if hasRunning(entries) {
+		fmt.Printf("%s+ the launcher left the pod running; the cost is up to when it exited%s\n", colorDim, colorReset)
+	}
+}
+
+func printCaseTotals(entries []CostEntry) {
+	type caseTotal struct {
+		pods int
+		cost float64
+		last time.Time
+	}
+	totals := make(map[string]*caseTotal)
+	for _, e := range entries {
+		name := e.Case
+		if name == "" {
+			name = "(no case)"
+		}
+		t := totals[name]
+		if t == nil {
+			t = &caseTotal{}
+			totals[name] = t
+		}
+		t.pods++
+		t.cost += e.Cost
+		if e.Created.After(t.last) {
+			t.last = e.Created
+		}
+	}
+	names := make([]string, 0, len(totals))
+	for name := range totals {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var total float64
+	fmt.Printf("%s%-24s %5s %9s  %s%s\n", colorDim, "Case", "Pods", "Cost", "Last launch", colorReset)
+	for _, name := range names {
+		t := totals[name]
+		fmt.Printf("%-24s %5d %9s  %s\n", truncate(name, 24), t.pods, fmt.Sprintf("$%.2f", t.cost), t.last.Local().Format("2006-01-02 15:04"))
+		total += t.cost
+	}
+	fmt.Printf("Total: $%.2f\n", total)
+	if hasRunning(entries) {
+		fmt.Printf("%sSome pods were left running; their cost is up to when the launcher exited%s\n", colorDim, colorReset)
+	}
+}
+
+func hasRunning(entries []CostEntry) bool {
+	for _, e := range entries {
+		if e.Running {
+			return true
+		}
+	}
+	return false
+}
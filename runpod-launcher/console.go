@@ -0,0 +1,322 @@
+// Copyright (c) 2025-2026 Mik Gangal
+// Licensed under CC BY-NC-SA 4.0 - https://creativecommons.org/licenses/by-nc-sa/4.0/
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Clear line helper - clears entire line
+const clearLine = "\r\033[K"
+
+var spinner = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// Tips to show while waiting
+var tips = []string{
+	"Your files persist on the network volume at /workspace",
+	"Use File Browser to drag & drop files directly to the pod",
+	"TurboVNC client gives better performance than browser",
+	"The nnInteractive server starts automatically with the desktop",
+	"Click '3D Slicer' on the desktop to start segmenting",
+	"GPU-accelerated apps: 3D Slicer, Blender, Fiji",
+	"SSH access: root / runpod (see connection info)",
+	"Claude Code CLI is pre-installed - just type 'claude'",
+	"Closing this window auto-terminates the pod",
+	"Balance updates every 5 minutes while running",
+	"T2 DICOM folders auto-load into Slicer when uploaded",
+	"lazygit is available via the GitHub desktop shortcut",
+}
+
+const exitPrompt = "Press Enter to TERMINATE pod and exit..."
+
+// consoleUI renders events as the classic scrolling console output
+type consoleUI struct {
+	mu          sync.Mutex
+	spinIdx     int
+	tipIdx      int
+	lastTipTime time.Time
+	inProgress  bool // a two-line status + tip display is on screen
+	promptShown bool
+	balanceSeen bool
+	muted       bool    // the dashboard owns the screen
+	phaseEnd    float64 // session time the last phase ended
+
+	// enter is closed when Enter is pressed; read once so a relaunched
+	// session (see spot.go) waits on the same key press
+	enter     chan struct{}
+	enterOnce sync.Once
+}
+
+func newConsoleUI() *consoleUI {
+	return &consoleUI{lastTipTime: time.Now()}
+}
+
+// clearProgress removes the status + tip lines so regular output can follow
+func (c *consoleUI) clearProgress() {
+	if !c.inProgress {
+		return
+	}
+	fmt.Print("\033[1B")        // Move down to tip line
+	fmt.Printf("%s", clearLine) // Clear tip line
+	fmt.Print("\033[1A")        // Move back up
+	fmt.Printf("%s", clearLine)
+	c.inProgress = false
+}
+
+// clearPrompt removes the exit prompt before printing over it
+func (c *consoleUI) clearPrompt() {
+	if c.promptShown {
+		fmt.Printf("%s", clearLine)
+	}
+}
+
+func (c *consoleUI) showPrompt() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clearProgress()
+	fmt.Print(exitPrompt)
+	c.promptShown = true
+}
+
+// setMuted pauses console output while another front end owns the terminal
+func (c *consoleUI) setMuted(muted bool) {
+	c.mu.Lock()
+	c.muted = muted
+	c.mu.Unlock()
+}
+
+func (c *consoleUI) handle(ev Event) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.muted {
+		return
+	}
+
+	if ev.Type != evProgress {
+		c.clearProgress()
+		c.clearPrompt()
+	}
+
+	switch ev.Type {
+	case evLaunching:
+		// Show technical details in compact format
+		fmt.Println()
+		fmt.Printf("%s── Configuration ──────────────────────────────────────────────%s\n", colorDim, colorReset)
+		fmt.Printf("%sTemplate: %s │ Volume: %s │ GPU: %s%s\n",
+			colorDim, ev.Template, ev.Volume, ev.GPU, colorReset)
+		fmt.Printf("%s───────────────────────────────────────────────────────────────%s\n", colorDim, colorReset)
+		fmt.Println()
+		fmt.Println("Launching pod...")
+
+	case evCreated:
+		fmt.Printf("  %s✓%s Pod created: %s\n", colorGreen, colorReset, ev.PodID)
+		if ev.GPU != "" {
+			fmt.Printf("  %s✓%s GPU: %s\n", colorGreen, colorReset, ev.GPU)
+		}
+
+	case evProgress:
+		c.spinIdx = (c.spinIdx + 1) % len(spinner)
+
+		// Rotate tips every 5 seconds
+		if time.Since(c.lastTipTime) > 5*time.Second {
+			c.tipIdx = (c.tipIdx + 1) % len(tips)
+			c.lastTipTime = time.Now()
+		}
+
+		statusLine := fmt.Sprintf("  %s %s", spinner[c.spinIdx], ev.Phase)
+		if ev.Detail != "" {
+			statusLine += fmt.Sprintf(" (%s)", ev.Detail)
+		}
+		statusLine += fmt.Sprintf(" - %s", formatDuration(ev.Elapsed()))
+
+		// Show status with tip on second line, then move the cursor
+		// back up one line for the next update
+		fmt.Printf("%s%s\n", clearLine, statusLine)
+		fmt.Printf("%s    %s💡 %s%s", clearLine, colorDim, tips[c.tipIdx], colorReset)
+		fmt.Print("\033[1A")
+		c.inProgress = true
+
+	case evPhaseDone:
+		took := time.Duration((ev.ElapsedSec - c.phaseEnd) * float64(time.Second))
+		c.phaseEnd = ev.ElapsedSec
+		fmt.Printf("%s  %s✓%s %s %s(%s)%s\n", clearLine, colorGreen, colorReset, ev.Phase, colorDim, formatDuration(took), colorReset)
+
+	case evReady:
+		c.printReady(ev)
+
+	case evFileBrowserReady:
+		fmt.Printf("  %s✓%s File Browser ready\n", colorGreen, colorReset)
+
+	case evFileBrowserMissing:
+		fmt.Printf("  %s⚠%s File Browser not detected (may need manual start)\n", colorYellow, colorReset)
+
+	case evBalance:
+		if !c.balanceSeen {
+			fmt.Printf("Balance: %s$%.2f%s │ Cost: %s$%.2f/hr%s │ Runtime: ~%.1f hrs\n",
+				colorGreen, ev.Balance, colorReset, colorRed, ev.CostPerHr, colorReset, ev.Balance/ev.CostPerHr)
+			c.balanceSeen = true
+		} else {
+			fmt.Printf("Balance: %s$%.2f%s │ Cost: %s$%.2f/hr%s │ Session: %s\n",
+				colorGreen, ev.Balance, colorReset, colorRed, ev.CostPerHr, colorReset,
+				formatDuration(ev.Elapsed()))
+		}
+
+	case evBudget:
+		if ev.Deadline != nil {
+			fmt.Printf("%s   Pod shuts itself down at %s%s\n", colorDim, ev.Deadline.Format("15:04"), colorReset)
+		}
+
+	case evBudgetThreshold, evIdle:
+		fmt.Printf("%sWarning: %s%s\n", colorYellow, ev.Message, colorReset)
+
+	case evRetry:
+		c.phaseEnd = ev.ElapsedSec
+		fmt.Printf("  %s↻ %s%s\n", colorYellow, ev.Message, colorReset)
+
+	case evTransfer:
+		t := ev.Transfer
+		switch {
+		case t.Error != "":
+			fmt.Printf("  %s✗%s %s %s failed: %s\n", colorRed, colorReset, t.Direction, t.Name, t.Error)
+		case t.Done:
+			fmt.Printf("  %s✓%s %s %s (%s)\n", colorGreen, colorReset, t.Direction, t.Name, formatBytes(t.Bytes))
+		}
+
+	case evLog:
+		switch ev.Level {
+		case levelOK:
+			fmt.Printf("  %s✓%s %s\n", colorGreen, colorReset, ev.Message)
+		case levelWarn:
+			fmt.Printf("%sWarning: %s%s\n", colorYellow, ev.Message, colorReset)
+		case levelError:
+			fmt.Printf("%sError: %s%s\n", colorRed, ev.Message, colorReset)
+		default:
+			fmt.Println(ev.Message)
+		}
+
+	case evStopped:
+		fmt.Printf("✓ Pod %s stopped\n", ev.PodID)
+
+	case evInterrupted:
+		fmt.Println()
+		fmt.Printf("%s╔════════════════════════════════════════════════════════════╗%s\n", colorYellow, colorReset)
+		fmt.Printf("%s║  SPOT POD INTERRUPTED                                      ║%s\n", colorYellow, colorReset)
+		fmt.Printf("%s╚════════════════════════════════════════════════════════════╝%s\n", colorYellow, colorReset)
+		fmt.Printf("%s  %s%s\n", colorYellow, ev.Message, colorReset)
+		fmt.Println()
+
+	case evTerminating:
+		fmt.Printf("\nTerminating pod %s...\n", ev.PodID)
+
+	case evTerminated:
+		fmt.Println("✓ Pod terminated successfully!")
+
+	case evTerminateFailed:
+		fmt.Println()
+		fmt.Printf("%s╔════════════════════════════════════════════════════════════╗%s\n", colorRed, colorReset)
+		fmt.Printf("%s║  POD TERMINATION COULD NOT BE CONFIRMED                    ║%s\n", colorRed, colorReset)
+		fmt.Printf("%s╚════════════════════════════════════════════════════════════╝%s\n", colorRed, colorReset)
+		fmt.Printf("%s  %s%s\n", colorRed, ev.Message, colorReset)
+		fmt.Printf("%s  The pod may still be running and billing your account!%s\n", colorRed, colorReset)
+		fmt.Println("  Check https://www.runpod.io/console/pods and terminate it manually.")
+		fmt.Println("  The launcher will retry the cleanup on its next run.")
+		fmt.Println()
+	}
+
+	if ev.Type != evProgress && c.promptShown {
+		fmt.Print(exitPrompt)
+	}
+}
+
+func (c *consoleUI) printReady(ev Event) {
+	fmt.Printf("\n%s✓ Ready in %s%s\n", colorGreen, formatDuration(ev.Elapsed()), colorReset)
+
+	// Display user-friendly connection info
+	fmt.Println()
+	fmt.Println("╔════════════════════════════════════════════════════════════╗")
+	fmt.Println("║  YOUR SESSION IS READY                                     ║")
+	fmt.Println("╠════════════════════════════════════════════════════════════╣")
+	fmt.Println("║                                                            ║")
+	fmt.Println("║  Desktop (opens automatically in browser):                 ║")
+	fmt.Printf("║    %s%s%s\n", colorCyan, ev.DesktopURL, colorReset)
+	fmt.Println("║                                                            ║")
+	fmt.Println("║  File Upload (drag & drop files):                          ║")
+	fmt.Printf("║    %s%s%s\n", colorCyan, ev.FilesURL, colorReset)
+	fmt.Printf("║    Login: %s%s%s / %s%s%s\n", colorGreen, fileBrowserUser, colorReset, colorGreen, fileBrowserPassword, colorReset)
+	fmt.Println("║                                                            ║")
+	if ev.Ports != nil {
+		fmt.Printf("%s║  Advanced: ", colorDim)
+		if port, ok := ev.Ports[5901]; ok {
+			fmt.Printf("VNC %s:%d ", port.IP, port.PublicPort)
+		}
+		if port, ok := ev.Ports[22]; ok {
+			fmt.Printf("│ SSH -p %d", port.PublicPort)
+		}
+		fmt.Printf("%s\n", colorReset)
+	}
+	fmt.Println("╚════════════════════════════════════════════════════════════╝")
+}
+
+// logSink writes events as timestamped plain lines (used by the watchdog)
+func logSink(ev Event) {
+	msg := ev.Message
+	switch ev.Type {
+	case evLog:
+	case evTerminating:
+		msg = "terminating pod"
+	case evTerminated:
+		msg = "pod terminated"
+	case evTerminateFailed:
+		msg = "termination could not be confirmed: " + ev.Message
+	default:
+		return
+	}
+	fmt.Printf("%s [%s] %s\n", ev.Time.Format("2006-01-02 15:04:05"), ev.PodID, msg)
+}
+
+// formatBytes formats a byte count in a human-friendly way
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// run shows the exit prompt and waits for Enter or the session budget to run out.
+// The console can't stop pods, so the pod is never kept.
+func (c *consoleUI) run(s *session) bool {
+	c.showPrompt()
+
+	c.enterOnce.Do(func() {
+		c.enter = make(chan struct{})
+		go func() {
+			bufio.NewReader(os.Stdin).ReadBytes('\n')
+			close(c.enter)
+		}()
+	})
+
+	select {
+	case <-c.enter:
+	case <-s.expired:
+		fmt.Println()
+	case <-s.quit:
+		fmt.Println()
+	}
+
+	c.mu.Lock()
+	c.promptShown = false
+	c.mu.Unlock()
+	return false
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2025-2026 Mik Gangal
+// Licensed under CC BY-NC-SA 4.0 - https://creativecommons.org/licenses/by-nc-sa/4.0/
+
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// The launch flow reports everything it does as events. Front ends (the
+// plain console output, the TUI dashboard, ...) subscribe to the events
+// instead of the launch code printing directly.
+
+// Event types
+const (
+	evLaunching          = "launching"
+	evCreated            = "created"
+	evProgress           = "progress"
+	evPhaseDone          = "phase_done"
+	evRetry              = "retry"
+	evReady              = "ready"
+	evFileBrowserReady   = "filebrowser_ready"
+	evFileBrowserMissing = "filebrowser_missing"
+	evBalance            = "balance"
+	evBudget             = "budget"
+	evBudgetThreshold    = "budget_threshold"
+	evIdle               = "idle"
+	evTransfer           = "transfer"
+	evLog                = "log"
+	evStopped            = "stopped"
+	evInterrupted        = "interrupted"
+	evTerminating        = "terminating"
+	evTerminated         = "terminated"
+	evTerminateFailed    = "terminate_failed"
+)
+
+// Log levels
+const (
+	levelInfo  = "info"
+	levelOK    = "ok"
+	levelWarn  = "warn"
+	levelError = "error"
+)
+
+// Event is a single thing that happened during a session
+type Event struct {
+	Type       string           `json:"event"`
+	Time       time.Time        `json:"time"`
+	PodID      string           `json:"podId,omitempty"`
+	GPU        string           `json:"gpu,omitempty"`
+	DataCenter string           `json:"dataCenter,omitempty"`
+	Template   string           `json:"template,omitempty"`
+	Volume     string           `json:"volume,omitempty"`
+	Phase      string           `json:"phase,omitempty"`
+	Detail     string           `json:"detail,omitempty"`
+	ElapsedSec float64          `json:"elapsedSec,omitempty"`
+	Level      string           `json:"level,omitempty"`
+	Message    string           `json:"message,omitempty"`
+	DesktopURL string           `json:"desktopUrl,omitempty"`
+	FilesURL   string           `json:"filesUrl,omitempty"`
+	Ports      map[int]PortInfo `json:"ports,omitempty"`
+	Balance    float64          `json:"balance,omitempty"`
+	CostPerHr  float64          `json:"costPerHr,omitempty"`
+	Deadline   *time.Time       `json:"deadline,omitempty"`
+	Percent    int              `json:"percent,omitempty"`
+	Transfer   *Transfer        `json:"transfer,omitempty"`
+}
+
+// Elapsed returns the event's session time as a duration
+func (e Event) Elapsed() time.Duration {
+	return time.Duration(e.ElapsedSec * float64(time.Second))
+}
+
+// Transfer describes the progress of an upload or download
+type Transfer struct {
+	Name      string  `json:"name"`
+	Direction string  `json:"direction"`
+	Bytes     int64   `json:"bytes"`
+	Total     int64   `json:"total,omitempty"`
+	Seconds   float64 `json:"seconds,omitempty"`
+	Done      bool    `json:"done,omitempty"`
+	Error     string  `json:"error,omitempty"`
+}
+
+type subscriber struct {
+	id int
+	fn func(Event)
+}
+
+var (
+	subscribersMu sync.Mutex
+	subscribers   []subscriber
+	nextSubID     int
+)
+
+// subscribe registers a handler that receives every event and returns a
+// function that removes it again
+func subscribe(fn func(Event)) func() {
+	subscribersMu.Lock()
+	defer subscribersMu.Unlock()
+	nextSubID++
+	id := nextSubID
+	subscribers = append(subscribers, subscriber{id: id, fn: fn})
+
+	return func() {
+		subscribersMu.Lock()
+		defer subscribersMu.Unlock()
+		for i, sub := range subscribers {
+			if sub.id == id {
+				subscribers = append(subscribers[:i:i], subscribers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// emit delivers an event to all subscribers
+func emit(ev Event) {
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	if ev.PodID == "" {
+		ev.PodID = activePodID
+	}
+
+	subscribersMu.Lock()
+	handlers := append([]subscriber{}, subscribers...)
+	subscribersMu.Unlock()
+
+	for _, sub := range handlers {
+		sub.fn(ev)
+	}
+}
+
+// logEvent emits a free-form log line
+func logEvent(level, format string, a ...interface{}) {
+	emit(Event{Type: evLog, Level: level, Message: fmt.Sprintf(format, a...)})
+}
+
+// sessionElapsed returns the time since launch in seconds
+func sessionElapsed() float64 {
+	if launchStart.IsZero() {
+		return 0
+	}
+	return time.Since(launchStart).Seconds()
+}
@@ -0,0 +1,296 @@
+// Copyright (c) 2025-2026 Mik Gangal
+// Licensed under CC BY-NC-SA 4.0 - https://creativecommons.org/licenses/by-nc-sa/4.0/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Remote "Create 3D Models": every Slicer on the pod polls /tmp for
+// slicer-export-trigger-* files (export-segments.py) and exports its
+// segments to a new Export_<timestamp> folder when one appears. The
+// launcher creates that file through the File Browser API, the same way
+// it writes the heartbeat, waits for the folder and downloads it. Pods
+// launched with --case name the folders Export_<case>_<timestamp>.
+
+const (
+	exportTriggerPrefix = "/tmp/slicer-export-trigger-"
+	exportWait          = 3 * time.Minute
+	exportPoll          = 2 * time.Second
+
+	// Slicer writes one of these last, so the folder is complete once it's there
+	exportSummaryFile = "EXPORT_SUMMARY.txt"
+	exportEmptyFile   = "NO_SEGMENTS_FOUND.txt"
+)
+
+// defaultExportDir is where exports are downloaded unless told otherwise
+func defaultExportDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "slicer-exports"
+	}
+	if info, err := os.Stat(filepath.Join(home, "Downloads")); err == nil && info.IsDir() {
+		return filepath.Join(home, "Downloads", "slicer-exports")
+	}
+	return filepath.Join(home, "slicer-exports")
+}
+
+// exportNames lists the Export_* folders in the transfer folder
+func exportNames(fb *fileBrowserClient) (map[string]bool, error) {
+	items, err := fb.list(transferDir)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool)
+	for _, item := range items {
+		if item.IsDir && strings.HasPrefix(item.Name, "Export_") {
+			names[item.Name] = true
+		}
+	}
+	return names, nil
+}
+
+// exportState reports whether Slicer has finished an export folder and
+// whether it found anything to export
+func exportState(fb *fileBrowserClient, name string) (done, empty bool) {
+	items, err := fb.list(transferDir + "/" + name)
+	if err != nil {
+		return false, false
+	}
+	for _, item := range items {
+		switch item.Name {
+		case exportSummaryFile:
+			return true, false
+		case exportEmptyFile:
+			return true, true
+		}
+	}
+	return false, false
+}
+
+// triggerExport asks every running Slicer on the pod to export its
+// segments and returns the new export folders. Each Slicer writes its own
+// folder, so it waits until every new one is complete.
+func triggerExport(fb *fileBrowserClient) ([]string, error) {
+	earlier, err := exportNames(fb)
+	if err != nil {
+		return nil, fmt.Errorf("could not list exports: %w", err)
+	}
+	trigger := fmt.Sprintf("%s%d", exportTriggerPrefix, time.Now().UnixNano())
+	if err := fb.writeFile(trigger, nil); err != nil {
+		return nil, fmt.Errorf("could not trigger the export: %w", err)
+	}
+
+	var ready []string
+	deadline := time.Now().Add(exportWait)
+	for time.Now().Before(deadline) {
+		time.Sleep(exportPoll)
+		names, err := exportNames(fb)
+		if err != nil {
+			continue
+		}
+		var pending []string
+		for name := range names {
+			if earlier[name] {
+				continue
+			}
+			done, empty := exportState(fb, name)
+			switch {
+			case !done:
+				pending = append(pending, name)
+			case empty:
+				logEvent(levelWarn, "Slicer had no segments to export (%s)", name)
+				earlier[name] = true
+			default:
+				ready = append(ready, name)
+				earlier[name] = true
+			}
+		}
+		if len(ready) > 0 && len(pending) == 0 {
+			return ready, nil
+		}
+	}
+	if len(ready) > 0 {
+		return ready, nil
+	}
+
+	// Nobody claimed the trigger; don't let a Slicer started later export by surprise
+	if items, err := fb.list(path.Dir(trigger)); err == nil {
+		for _, item := range items {
+			if item.Name == path.Base(trigger) {
+				fb.remove(trigger)
+				return nil, fmt.Errorf("no Slicer picked up the export - is Slicer running on the desktop?")
+			}
+		}
+	}
+	return nil, fmt.Errorf("no export appeared within %s", formatDuration(exportWait))
+}
+
+// exportModels runs a remote export and downloads the results into dir
+func (s *session) exportModels(dir string) {
+	s.mu.Lock()
+	if s.exporting {
+		s.mu.Unlock()
+		logEvent(levelWarn, "An export is already running")
+		return
+	}
+	s.exporting = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.exporting = false
+		s.mu.Unlock()
+	}()
+
+	logEvent(levelInfo, "Creating 3D models on the pod...")
+	names, err := triggerExport(s.fb)
+	if err != nil {
+		logEvent(levelError, "Export failed: %v", err)
+		return
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		logEvent(levelError, "Export failed: %v", err)
+		return
+	}
+	var saved []string
+	for _, name := range names {
+		dest := filepath.Join(dir, caseExportName(activeCase, name)+".zip")
+		f, err := os.Create(dest)
+		if err != nil {
+			logEvent(levelError, "Download of %s failed: %v", name, err)
+			continue
+		}
+		err = s.downloadExport(name, f)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			os.Remove(dest)
+			logEvent(levelError, "Download of %s failed: %v", name, err)
+			continue
+		}
+		logEvent(levelOK, "Saved %s", dest)
+		saved = append(saved, dest)
+	}
+	if len(saved) > 0 {
+		logMeshChecks(saved)
+	}
+	printExports(saved)
+}
+
+// runExportCommand is the export subcommand: export and download from a
+// running pod without the launch session
+func runExportCommand(args []string) int {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	podID := fs.String("pod", "", "pod to export from (default: the running pod this launcher started)")
+	outDir := fs.String("o", defaultExportDir(), "folder the exports are downloaded to")
+	printer := fs.String("print", "", "hand the exports to this printer from settings.json (default: printExports)")
+	caseFlag := fs.String("case", "", "tag the downloads with this case ID (default: the case in the pod name)")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if *caseFlag != "" {
+		if err := validCaseID(*caseFlag); err != nil {
+			fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+			return exitUsage
+		}
+	}
+
+	apiKey, err := commandSetup()
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitUsage
+	}
+	pod, err := exportPod(apiKey, *podID)
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitUsage
+	}
+
+	if *printer == "" {
+		*printer = appSettings.PrintExports
+	}
+	if *printer != "" {
+		if _, err := appSettings.printer(*printer); err != nil {
+			fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+			return exitUsage
+		}
+	}
+
+	// Launches with --case carry it in the pod name
+	caseID := *caseFlag
+	if caseID == "" {
+		caseID = caseFromPodName(pod.Name)
+	}
+
+	subscribe(commandLogPrinter)
+	fmt.Printf("Creating 3D models on %s (%s)...\n", pod.Name, pod.ID)
+	fb := newFileBrowserClient(pod.ID)
+	if password := fleetPassword(pod.ID); password != "" {
+		fb.password = password
+	}
+	names, err := triggerExport(fb)
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitError
+	}
+	code := exitOK
+	var saved []string
+	for _, name := range names {
+		dest := filepath.Join(*outDir, caseExportName(caseID, name)+".zip")
+		if err := archiveExport(fb, transferDir+"/"+name, dest); err != nil {
+			fmt.Printf("%sError: %s: %v%s\n", colorRed, name, err, colorReset)
+			code = exitError
+			continue
+		}
+		saved = append(saved, dest)
+	}
+	if len(saved) > 0 {
+		logMeshChecks(saved)
+	}
+	if *printer != "" && len(saved) > 0 {
+		if err := printTo(*printer, saved); err != nil {
+			fmt.Printf("%sError: print handoff to %s failed: %v%s\n", colorRed, *printer, err, colorReset)
+			code = exitError
+		}
+	}
+	return code
+}
+
+// exportPod picks the pod to export from: the given one, or the only
+// running pod the launcher started
+func exportPod(apiKey, podID string) (PodSummary, error) {
+	pods, err := listPods(apiKey)
+	if err != nil {
+		return PodSummary{}, err
+	}
+	var running []PodSummary
+	for _, p := range pods {
+		if podID != "" && p.ID == podID {
+			return p, nil
+		}
+		if p.Mine && p.DesiredStatus == "RUNNING" {
+			running = append(running, p)
+		}
+	}
+	switch {
+	case podID != "":
+		return PodSummary{}, fmt.Errorf("no pod %s in this account", podID)
+	case len(running) == 0:
+		return PodSummary{}, fmt.Errorf("no running pod started by this launcher; pick one with -pod")
+	case len(running) > 1:
+		var ids []string
+		for _, p := range running {
+			ids = append(ids, p.ID)
+		}
+		return PodSummary{}, fmt.Errorf("several running pods (%s); pick one with -pod", strings.Join(ids, ", "))
+	}
+	return running[0], nil
+}
@@ -0,0 +1,262 @@
+// Copyright (c) 2025-2026 Mik Gangal
+// Licensed under CC BY-NC-SA 4.0 - https://creativecommons.org/licenses/by-nc-sa/4.0/
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// File Browser runs on the pod (port 8080) with "/" as its root, so its
+// REST API gives the launcher read/write access to the pod's filesystem
+// without needing SSH.
+
+const (
+	fileBrowserPort     = 8080
+	fileBrowserUser     = "admin"
+	fileBrowserPassword = "runpod"
+
+	// transferDir is where uploads land and Slicer writes its Export_* folders
+	transferDir = "/FILE TRANSFERS"
+)
+
+type fileBrowserClient struct {
+	baseURL  string
+	username string
+	password string
+	client   *http.Client
+
+	mu    sync.Mutex
+	token string
+
+	// transfers has no overall timeout so large uploads and downloads can finish
+	transfers *http.Client
+}
+
+func newFileBrowserClient(podID string) *fileBrowserClient {
+	return &fileBrowserClient{
+		baseURL:   podURL(podID, fileBrowserPort),
+		username:  fileBrowserUser,
+		password:  fileBrowserPassword,
+		client:    newHTTPClient(30 * time.Second),
+		transfers: newHTTPClient(0),
+	}
+}
+
+// fileBrowserItem is an entry of a directory listing
+type fileBrowserItem struct {
+	Name    string    `json:"name"`
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	IsDir   bool      `json:"isDir"`
+	ModTime time.Time `json:"modified"`
+}
+
+func (fb *fileBrowserClient) login() error {
+	body, _ := json.Marshal(map[string]string{
+		"username": fb.username,
+		"password": fb.password,
+	})
+
+	resp, err := fb.client.Post(fb.baseURL+"/api/login", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("File Browser login failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("File Browser login failed (%d): %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	token := strings.TrimSpace(string(data))
+	addSecret(token)
+	fb.mu.Lock()
+	fb.token = token
+	fb.mu.Unlock()
+	return nil
+}
+
+// escapePath URL-escapes each segment of a pod path like "/FILE TRANSFERS/x"
+func escapePath(p string) string {
+	parts := strings.Split(p, "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+	return strings.Join(parts, "/")
+}
+
+// do sends an authenticated request, logging in first and once more if the token expired
+func (fb *fileBrowserClient) do(method, apiPath, query string, body []byte) (*http.Response, error) {
+	fb.mu.Lock()
+	loggedIn := fb.token != ""
+	fb.mu.Unlock()
+	if !loggedIn {
+		if err := fb.login(); err != nil {
+			return nil, err
+		}
+	}
+
+	for attempt := 0; attempt < 2; attempt++ {
+		var reader io.Reader
+		if body != nil {
+			reader = bytes.NewReader(body)
+		}
+		req, err := fb.newRequest(method, apiPath, query, reader)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := fb.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("File Browser request failed: %w", err)
+		}
+		if resp.StatusCode != http.StatusUnauthorized || attempt == 1 {
+			return resp, nil
+		}
+		resp.Body.Close()
+		if err := fb.login(); err != nil {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("File Browser request failed")
+}
+
+func (fb *fileBrowserClient) newRequest(method, apiPath, query string, body io.Reader) (*http.Request, error) {
+	u := fb.baseURL + apiPath
+	if query != "" {
+		u += "?" + query
+	}
+	req, err := http.NewRequest(method, u, body)
+	if err != nil {
+		return nil, fmt.Errorf("could not create request: %w", err)
+	}
+	fb.mu.Lock()
+	req.Header.Set("X-Auth", fb.token)
+	fb.mu.Unlock()
+	return req, nil
+}
+
+// stream sends a request on the transfer client. A streamed body can't be
+// replayed, so the login happens up front instead of on a 401.
+func (fb *fileBrowserClient) stream(method, apiPath, query string, body io.Reader, size int64) (*http.Response, error) {
+	if err := fb.login(); err != nil {
+		return nil, err
+	}
+	req, err := fb.newRequest(method, apiPath, query, body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.ContentLength = size
+	}
+	resp, err := fb.transfers.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("File Browser request failed: %w", err)
+	}
+	return resp, nil
+}
+
+// list returns the entries of a directory on the pod
+func (fb *fileBrowserClient) list(podPath string) ([]fileBrowserItem, error) {
+	resp, err := fb.do("GET", "/api/resources"+escapePath(podPath)+"/", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not list %s (%d): %s", podPath, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	var listing struct {
+		Items []fileBrowserItem `json:"items"`
+	}
+	if err := json.Unmarshal(data, &listing); err != nil {
+		return nil, fmt.Errorf("could not parse listing of %s: %w", podPath, err)
+	}
+	return listing.Items, nil
+}
+
+// getJSON decodes the answer to a GET request
+func (fb *fileBrowserClient) getJSON(apiPath string, out interface{}) error {
+	resp, err := fb.do("GET", apiPath, "", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("File Browser request %s failed (%d): %s", apiPath, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	return json.Unmarshal(data, out)
+}
+
+// remove deletes a file or directory on the pod
+func (fb *fileBrowserClient) remove(podPath string) error {
+	resp, err := fb.do("DELETE", "/api/resources"+escapePath(podPath), "", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("could not delete %s (%d): %s", podPath, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	return nil
+}
+
+// upload streams size bytes from r into a file on the pod. File Browser
+// creates missing parent directories.
+func (fb *fileBrowserClient) upload(podPath string, r io.Reader, size int64) error {
+	resp, err := fb.stream("POST", "/api/resources"+escapePath(podPath), "override=true", r, size)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("could not upload %s (%d): %s", podPath, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	return nil
+}
+
+// download opens a file on the pod for reading. Directories come back as a
+// zip archive. The caller closes the body.
+func (fb *fileBrowserClient) download(podPath string) (io.ReadCloser, int64, error) {
+	resp, err := fb.stream("GET", "/api/raw"+escapePath(podPath), "algo=zip", nil, 0)
+	if err != nil {
+		return nil, 0, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, 0, fmt.Errorf("could not download %s (%d): %s", podPath, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	return resp.Body, resp.ContentLength, nil
+}
+
+// writeFile creates or overwrites a file on the pod
+func (fb *fileBrowserClient) writeFile(podPath string, content []byte) error {
+	resp, err := fb.do("POST", "/api/resources"+escapePath(podPath), "override=true", content)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("could not write %s (%d): %s", podPath, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	return nil
+}
@@ -0,0 +1,873 @@
+// Copyright (c) 2025-2026 Mik Gangal
+// Licensed under CC BY-NC-SA 4.0 - https://creativecommons.org/licenses/by-nc-sa/4.0/
+
+package main
+
+import (
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"html/template"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// A fleet is a set of identical pods for a class or workshop, launched
+// together from one profile. Every seat gets its own password, and a roster
+// (CSV plus a printable page with QR codes) hands out the links. The pods
+// end themselves at the fleet's end time - which is also how the fleet
+// budget is enforced - so the launcher does not need to keep running.
+
+const (
+	fleetDirName        = "fleets"
+	maxFleetSize        = 50
+	defaultFleetHours   = 3
+	minFleetRuntime     = 15 * time.Minute
+	fleetLaunchStagger  = 500 * time.Millisecond
+	fleetPasswordLength = 8 // VNC passwords are cut off after 8 characters
+	fleetPasswordChars  = "abcdefghjkmnpqrstuvwxyz23456789"
+)
+
+// Seat statuses
+const (
+	seatLaunching  = "launching"
+	seatReady      = "ready"
+	seatFailed     = "failed"
+	seatTerminated = "terminated"
+)
+
+var fleetNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,31}$`)
+
+// FleetSeat is one participant's pod
+type FleetSeat struct {
+	Seat       int       `json:"seat"`
+	PodID      string    `json:"podId,omitempty"`
+	GPU        string    `json:"gpu,omitempty"`
+	CostPerHr  float64   `json:"costPerHr,omitempty"`
+	Password   string    `json:"password"`
+	DesktopURL string    `json:"desktopUrl,omitempty"`
+	FilesURL   string    `json:"filesUrl,omitempty"`
+	Status     string    `json:"status"`
+	Error      string    `json:"error,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+	ReadySec   float64   `json:"readySec,omitempty"`
+}
+
+func (s *FleetSeat) label() string {
+	return fmt.Sprintf("%02d", s.Seat)
+}
+
+// Fleet is saved as fleets/<name>.json in the state directory, so the pods
+// can be checked on and torn down from any later run
+type Fleet struct {
+	Name      string      `json:"name"`
+	Profile   string      `json:"profile"`
+	Account   string      `json:"account,omitempty"` // saved account the pods run on ("" = default)
+	CreatedAt time.Time   `json:"createdAt"`
+	EndsAt    time.Time   `json:"endsAt"`
+	Budget    float64     `json:"budget,omitempty"`
+	Seats     []FleetSeat `json:"seats"`
+
+	mu sync.Mutex
+}
+
+func fleetPath(name string) (string, error) {
+	dir, err := getStateDir()
+	if err != nil {
+		return "", err
+	}
+	dir = filepath.Join(dir, fleetDirName)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("could not create fleet directory: %w", err)
+	}
+	return filepath.Join(dir, name+".json"), nil
+}
+
+// loadFleet returns the saved fleet, or nil if there is none by that name
+func loadFleet(name string) (*Fleet, error) {
+	path, err := fleetPath(name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read fleet file: %w", err)
+	}
+	f := &Fleet{}
+	if err := json.Unmarshal(data, f); err != nil {
+		return nil, fmt.Errorf("could not parse fleet file: %w", err)
+	}
+	return f, nil
+}
+
+// listFleets returns the names of all saved fleets
+func listFleets() ([]string, error) {
+	path, err := fleetPath("x")
+	if err != nil {
+		return nil, err
+	}
+	matches, err := filepath.Glob(filepath.Join(filepath.Dir(path), "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, m := range matches {
+		names = append(names, strings.TrimSuffix(filepath.Base(m), ".json"))
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// save writes the fleet file. It holds the seat passwords, so it is only
+// readable by the user.
+func (f *Fleet) save() error {
+	f.mu.Lock()
+	data, err := json.MarshalIndent(f, "", "  ")
+	f.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("could not encode fleet: %w", err)
+	}
+
+	path, err := fleetPath(f.Name)
+	if err != nil {
+		return err
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("could not write fleet file: %w", err)
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// update changes a seat under the lock and saves the fleet right away, so
+// a crashed launcher never loses track of a pod
+func (f *Fleet) update(seat int, fn func(s *FleetSeat)) {
+	f.mu.Lock()
+	fn(&f.Seats[seat-1])
+	f.mu.Unlock()
+	if err := f.save(); err != nil {
+		logEvent(levelWarn, "Could not save fleet: %v", err)
+	}
+}
+
+// seatFor returns the seat of a pod, or nil
+func (f *Fleet) seatFor(podID string) *FleetSeat {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i := range f.Seats {
+		if f.Seats[i].PodID == podID {
+			return &f.Seats[i]
+		}
+	}
+	return nil
+}
+
+// livePods returns the seats whose pods may still exist
+func (f *Fleet) livePods() []FleetSeat {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var live []FleetSeat
+	for _, s := range f.Seats {
+		if s.PodID != "" && s.Status != seatTerminated {
+			live = append(live, s)
+		}
+	}
+	return live
+}
+
+// costPerHr is what the fleet's pods cost together
+func (f *Fleet) costPerHr() float64 {
+	total := 0.0
+	for _, s := range f.livePods() {
+		total += s.CostPerHr
+	}
+	return total
+}
+
+// fleetPassword returns the seat password of a fleet pod, or ""
+func fleetPassword(podID string) string {
+	names, _ := listFleets()
+	for _, name := range names {
+		f, err := loadFleet(name)
+		if err != nil || f == nil {
+			continue
+		}
+		if s := f.seatFor(podID); s != nil {
+			return s.Password
+		}
+	}
+	return ""
+}
+
+func runFleetCommand(args []string) int {
+	if len(args) == 0 {
+		fmt.Println("usage: slicer-launcher fleet launch -n N [-name class] [-hours 3 | -until 16:30] [-budget USD]")
+		fmt.Println("       slicer-launcher fleet status | roster | down [-name class]")
+		return exitUsage
+	}
+	switch args[0] {
+	case "launch":
+		return runFleetLaunch(args[1:])
+	case "status":
+		return runFleetStatus(args[1:])
+	case "roster":
+		return runFleetRoster(args[1:])
+	case "down":
+		return runFleetDown(args[1:])
+	}
+	fmt.Printf("unknown fleet command %q\n", args[0])
+	return exitUsage
+}
+
+func runFleetLaunch(args []string) int {
+	fs := flag.NewFlagSet("fleet launch", flag.ContinueOnError)
+	count := fs.Int("n", 0, "number of pods (seats)")
+	name := fs.String("name", "class", "fleet name, used for the pod names and the roster")
+	profileName := fs.String("profile", defaultProfileName, "settings profile the pods are created from")
+	hours := fs.Float64("hours", defaultFleetHours, "how long the pods run")
+	until := fs.String("until", "", "end time instead of -hours (15:04 today, or 2006-01-02 15:04)")
+	budget := fs.Float64("budget", 0, "most the whole fleet may cost in USD (0 = no limit)")
+	outDir := fs.String("o", "", "roster directory (default <name>-roster)")
+	launchTimeout := fs.Duration("launch-timeout", defaultLaunchTimeout, "give up on seats not ready after this long")
+	yes := fs.Bool("yes", false, "launch without asking for confirmation")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	switch {
+	case *count < 1 || *count > maxFleetSize:
+		fmt.Printf("-n must be between 1 and %d\n", maxFleetSize)
+		return exitUsage
+	case !fleetNamePattern.MatchString(*name):
+		fmt.Println("-name must be up to 32 lowercase letters, digits and dashes")
+		return exitUsage
+	case *budget < 0 || *launchTimeout <= 0:
+		fmt.Println("-budget and -launch-timeout must be positive")
+		return exitUsage
+	}
+
+	start := time.Now()
+	endsAt := start.Add(time.Duration(*hours * float64(time.Hour)))
+	if *until != "" {
+		t, err := parseEndTime(*until, start)
+		if err != nil {
+			fmt.Printf("-until: %v\n", err)
+			return exitUsage
+		}
+		endsAt = t
+	}
+
+	apiKey, err := commandSetup()
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitUsage
+	}
+	profile, err := appSettings.profile(*profileName)
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitUsage
+	}
+	account, apiKey, err := profileAPIKey(profile, apiKey)
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitUsage
+	}
+
+	if err := prepareVolume(apiKey, &profile); err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitUsage
+	}
+
+	if existing, err := loadFleet(*name); err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitError
+	} else if existing != nil && len(existing.livePods()) > 0 {
+		fmt.Printf("Fleet %q already has pods. Run 'slicer-launcher fleet down -name %s' first or pick another -name.\n", *name, *name)
+		return exitUsage
+	}
+
+	// The budget becomes an earlier end time, worked out from the most
+	// expensive GPU type the pods might get
+	fmt.Printf("Fleet %q: %d pods from profile %q\n", *name, *count, profile.Name)
+	info, err := checkBalance(apiKey, profile, *count)
+	if info != nil {
+		fmt.Printf("  Account: %s, balance $%.2f\n", accountLabel(account), info.Balance)
+	}
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitLaunchFailed
+	}
+	if *budget > 0 {
+		rate, err := maxGPUPrice(apiKey, profile.GPUTypes)
+		if err != nil {
+			fmt.Printf("%sError: could not check GPU prices for the budget: %v%s\n", colorRed, err, colorReset)
+			return exitError
+		}
+		hoursLeft := *budget / (float64(*count) * rate)
+		byBudget := start.Add(time.Duration(hoursLeft * float64(time.Hour)))
+		fmt.Printf("  Budget:  $%.2f = %s at up to %d × $%.2f/hr\n", *budget, formatDuration(byBudget.Sub(start)), *count, rate)
+		if byBudget.Before(endsAt) {
+			endsAt = byBudget
+		}
+	}
+	if endsAt.Sub(start) < minFleetRuntime {
+		fmt.Printf("%sError: the pods would run less than %s%s\n", colorRed, formatDuration(minFleetRuntime), colorReset)
+		return exitUsage
+	}
+	fmt.Printf("  Ends:    %s (%s) - the pods terminate themselves\n", endsAt.Format("15:04"), formatDuration(endsAt.Sub(start)))
+	if !*yes && !confirm("Launch? (y/n): ") {
+		return exitOK
+	}
+
+	closeLog, err := initLogging(false)
+	if err != nil {
+		logEvent(levelWarn, "Session log disabled: %v", err)
+	}
+	atExit(closeLog)
+	subscribe((&slogSink{}).handle)
+
+	fleet := &Fleet{Name: *name, Profile: profile.Name, Account: account, CreatedAt: start, EndsAt: endsAt, Budget: *budget}
+	for i := 1; i <= *count; i++ {
+		password, err := randomPassword()
+		if err != nil {
+			fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+			return exitError
+		}
+		addSecret(password)
+		fleet.Seats = append(fleet.Seats, FleetSeat{Seat: i, Password: password, Status: seatLaunching})
+	}
+	if err := fleet.save(); err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitError
+	}
+
+	launchStart = start
+	unsubscribe := subscribe(newFleetPrinter(fleet).handle)
+	defer unsubscribe()
+
+	// Ctrl-C tears down whatever was created so far
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	defer signal.Stop(sig)
+	launched := make(chan struct{})
+	go func() {
+		select {
+		case <-sig:
+			fmt.Println("\nInterrupted - terminating the fleet's pods...")
+			fleetDown(apiKey, fleet)
+			fmt.Printf("Pods still being created will end at %s. Run 'slicer-launcher fleet down -name %s' to be sure.\n",
+				endsAt.Format("15:04"), fleet.Name)
+			exit(exitError)
+		case <-launched:
+		}
+	}()
+
+	fmt.Println()
+	limits := launchLimits{queue: defaultQueueTimeout, pull: defaultPullTimeout, deadline: start.Add(*launchTimeout)}
+	var wg sync.WaitGroup
+	for i := range fleet.Seats {
+		wg.Add(1)
+		go func(seat int) {
+			defer wg.Done()
+			launchSeat(apiKey, fleet, seat, profile, limits)
+		}(fleet.Seats[i].Seat)
+		time.Sleep(fleetLaunchStagger)
+	}
+	wg.Wait()
+	close(launched)
+
+	dir := *outDir
+	if dir == "" {
+		dir = fleet.Name + "-roster"
+	}
+	if err := writeRoster(fleet, dir); err != nil {
+		logEvent(levelError, "Could not write the roster: %v", err)
+	}
+
+	ready := 0
+	for _, s := range fleet.Seats {
+		if s.Status == seatReady {
+			ready++
+		}
+	}
+	fmt.Println()
+	printFleet(fleet, nil)
+	fmt.Println()
+	fmt.Printf("%d of %d seats ready │ %s$%.2f/hr%s │ ends %s\n", ready, len(fleet.Seats), colorRed, fleet.costPerHr(), colorReset, endsAt.Format("15:04"))
+	fmt.Printf("Roster: %s\n", filepath.Join(dir, "roster.html"))
+	fmt.Printf("End early with: slicer-launcher fleet down -name %s\n", fleet.Name)
+
+	switch {
+	case ready == len(fleet.Seats):
+		return exitOK
+	case ready == 0:
+		return exitLaunchFailed
+	}
+	return exitNotReady
+}
+
+// launchSeat creates one seat's pod and waits for its desktop. Seats that
+// don't come up by the launch deadline are terminated.
+func launchSeat(apiKey string, fleet *Fleet, seat int, profile Profile, limits launchLimits) {
+	password := fleet.Seats[seat-1].Password
+	_, _, err := launchReady(apiKey, fmt.Sprintf("%s-%02d", fleet.Name, seat), profile, limits,
+		func() (map[string]string, error) {
+			// The pods end themselves at the fleet's end time, with or without us
+			env, err := deadlineEnv(fleet.EndsAt)
+			if err != nil {
+				return nil, err
+			}
+			env["SLICER_PASSWORD"] = password
+			return env, nil
+		},
+		func(pod *PodResponse) {
+			fleet.update(seat, func(s *FleetSeat) {
+				s.PodID, s.GPU, s.CostPerHr = pod.ID, pod.Machine.GpuDisplayName, pod.CostPerHr
+				s.DesktopURL = podURL(pod.ID, desktopPort)
+				s.FilesURL = podURL(pod.ID, fileBrowserPort) + "/FILE%20TRANSFERS/"
+				s.CreatedAt = time.Now()
+			})
+		})
+	if err != nil {
+		fleet.update(seat, func(s *FleetSeat) { s.Status, s.Error = seatFailed, err.Error() })
+		logEvent(levelError, "Seat %02d: %v", seat, err)
+		return
+	}
+	fleet.update(seat, func(s *FleetSeat) { s.Status, s.ReadySec = seatReady, sessionElapsed() })
+}
+
+// fleetPrinter shows the launch progress of all seats as one line per
+// phase change
+type fleetPrinter struct {
+	mu     sync.Mutex
+	fleet  *Fleet
+	phases map[string]string // pod ID -> phase shown last
+}
+
+func newFleetPrinter(f *Fleet) *fleetPrinter {
+	return &fleetPrinter{fleet: f, phases: make(map[string]string)}
+}
+
+func (p *fleetPrinter) handle(ev Event) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	seat := "--"
+	if s := p.fleet.seatFor(ev.PodID); s != nil {
+		seat = s.label()
+	}
+	at := formatDuration(ev.Elapsed())
+
+	switch ev.Type {
+	case evCreated:
+		fmt.Printf("  [%s] %s✓%s Pod %s (%s, $%.2f/hr)\n", seat, colorGreen, colorReset, ev.PodID, ev.GPU, ev.CostPerHr)
+	case evProgress:
+		if ev.PodID == "" || p.phases[ev.PodID] == ev.Phase {
+			return
+		}
+		p.phases[ev.PodID] = ev.Phase
+		fmt.Printf("  [%s] %s%s - %s%s\n", seat, colorDim, ev.Phase, at, colorReset)
+	case evPhaseDone:
+		if ev.Phase == phaseDesktopReady {
+			fmt.Printf("  [%s] %s✓ Desktop ready%s - %s\n", seat, colorGreen, colorReset, at)
+		}
+	case evRetry:
+		fmt.Printf("  [%s] %s↻ %s%s\n", seat, colorYellow, ev.Message, colorReset)
+	case evLog:
+		switch ev.Level {
+		case levelWarn:
+			fmt.Printf("%sWarning: %s%s\n", colorYellow, ev.Message, colorReset)
+		case levelError:
+			fmt.Printf("%sError: %s%s\n", colorRed, ev.Message, colorReset)
+		}
+	}
+}
+
+// printFleet lists the seats, with the pods' current RunPod status if known
+func printFleet(f *Fleet, live map[string]string) {
+	fmt.Printf("  %s%-4s %-16s %-22s %-10s %s%s\n", colorDim, "Seat", "Pod", "GPU", "Status", "Desktop", colorReset)
+	for _, s := range f.Seats {
+		status := s.Status
+		if st, ok := live[s.PodID]; ok {
+			status = st
+		}
+		color := colorReset
+		switch status {
+		case seatReady, "RUNNING":
+			color = colorGreen
+		case seatFailed:
+			color = colorRed
+		}
+		fmt.Printf("  %-4s %-16s %-22s %s%-10s%s %s\n", s.label(), s.PodID, s.GPU, color, status, colorReset, s.DesktopURL)
+		if s.Error != "" {
+			fmt.Printf("  %s     %s%s\n", colorDim, s.Error, colorReset)
+		}
+	}
+}
+
+// pickFleet resolves -name, defaulting to the only saved fleet
+func pickFleet(name string) (*Fleet, error) {
+	if name == "" {
+		names, err := listFleets()
+		if err != nil {
+			return nil, err
+		}
+		switch len(names) {
+		case 0:
+			return nil, fmt.Errorf("no fleets - start one with 'slicer-launcher fleet launch -n N'")
+		case 1:
+			name = names[0]
+		default:
+			return nil, fmt.Errorf("several fleets (%s) - pick one with -name", strings.Join(names, ", "))
+		}
+	}
+	f, err := loadFleet(name)
+	if err == nil && f == nil {
+		err = fmt.Errorf("no fleet named %q", name)
+	}
+	return f, err
+}
+
+func runFleetStatus(args []string) int {
+	fs := flag.NewFlagSet("fleet status", flag.ContinueOnError)
+	name := fs.String("name", "", "fleet name (default: the only fleet)")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	apiKey, err := commandSetup()
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitUsage
+	}
+	f, err := pickFleet(*name)
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitError
+	}
+	if apiKey, err = accountAPIKey(f.Account, apiKey); err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitUsage
+	}
+
+	// Ask RunPod about every pod the fleet still knows
+	live := make(map[string]string)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, s := range f.livePods() {
+		wg.Add(1)
+		go func(podID string) {
+			defer wg.Done()
+			status, err := getPodStatus(apiKey, podID)
+			switch {
+			case err != nil:
+				status = "unknown"
+			case status == "":
+				status = "gone"
+			}
+			mu.Lock()
+			live[podID] = status
+			mu.Unlock()
+		}(s.PodID)
+	}
+	wg.Wait()
+
+	// Spend so far, counting pods RunPod still has until now or the end time
+	now := time.Now()
+	spent, running := 0.0, 0
+	for _, s := range f.livePods() {
+		if st := live[s.PodID]; st == "gone" || s.CreatedAt.IsZero() {
+			continue
+		}
+		running++
+		end := now
+		if f.EndsAt.Before(end) {
+			end = f.EndsAt
+		}
+		spent += s.CostPerHr * end.Sub(s.CreatedAt).Hours()
+	}
+
+	fmt.Printf("Fleet %q (profile %s) - %d seats, started %s\n", f.Name, f.Profile, len(f.Seats), f.CreatedAt.Format("2006-01-02 15:04"))
+	fmt.Println()
+	printFleet(f, live)
+	fmt.Println()
+	if now.Before(f.EndsAt) {
+		fmt.Printf("%d pods up │ ends %s (in %s)\n", running, f.EndsAt.Format("15:04"), formatDuration(f.EndsAt.Sub(now)))
+	} else {
+		fmt.Printf("%d pods up │ ended %s\n", running, f.EndsAt.Format("15:04"))
+	}
+	if f.Budget > 0 {
+		fmt.Printf("Spent about $%.2f of $%.2f\n", spent, f.Budget)
+	} else {
+		fmt.Printf("Spent about $%.2f\n", spent)
+	}
+	return exitOK
+}
+
+func runFleetRoster(args []string) int {
+	fs := flag.NewFlagSet("fleet roster", flag.ContinueOnError)
+	name := fs.String("name", "", "fleet name (default: the only fleet)")
+	outDir := fs.String("o", "", "roster directory (default <name>-roster)")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	f, err := pickFleet(*name)
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitError
+	}
+	dir := *outDir
+	if dir == "" {
+		dir = f.Name + "-roster"
+	}
+	if err := writeRoster(f, dir); err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitError
+	}
+	fmt.Printf("Roster written to %s\n", dir)
+	return exitOK
+}
+
+func runFleetDown(args []string) int {
+	fs := flag.NewFlagSet("fleet down", flag.ContinueOnError)
+	name := fs.String("name", "", "fleet name (default: the only fleet)")
+	yes := fs.Bool("yes", false, "terminate without asking for confirmation")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	apiKey, err := commandSetup()
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitUsage
+	}
+	f, err := pickFleet(*name)
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitError
+	}
+	if apiKey, err = accountAPIKey(f.Account, apiKey); err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitUsage
+	}
+
+	pods := f.livePods()
+	if !*yes && !confirm(fmt.Sprintf("Terminate the %d pods of fleet %q? (y/n): ", len(pods), f.Name)) {
+		return exitOK
+	}
+	if !fleetDown(apiKey, f) {
+		return exitError
+	}
+
+	// Nothing left to track; the roster stays where it was written
+	if path, err := fleetPath(f.Name); err == nil {
+		os.Remove(path)
+	}
+	fmt.Printf("✓ Fleet %q is down\n", f.Name)
+	return exitOK
+}
+
+// fleetDown terminates all of the fleet's pods at once and reports whether
+// every termination was confirmed
+func fleetDown(apiKey string, f *Fleet) bool {
+	seats := make(map[string]FleetSeat)
+	var ids []string
+	for _, s := range f.livePods() {
+		seats[s.PodID] = s
+		ids = append(ids, s.PodID)
+	}
+	failed := deletePods(apiKey, ids, func(podID string) {
+		s := seats[podID]
+		f.update(s.Seat, func(s *FleetSeat) { s.Status = seatTerminated })
+		fmt.Printf("  [%s] %s✓%s Pod %s terminated\n", s.label(), colorGreen, colorReset, podID)
+	})
+	return len(failed) == 0
+}
+
+// parseEndTime reads "15:04" (today) or "2006-01-02 15:04" in local time
+func parseEndTime(s string, now time.Time) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02 15:04", s, time.Local); err == nil {
+		if !t.After(now) {
+			return time.Time{}, fmt.Errorf("%s is in the past", s)
+		}
+		return t, nil
+	}
+	clock, err := time.ParseInLocation("15:04", s, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("use 15:04 or 2006-01-02 15:04")
+	}
+	t := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, time.Local)
+	if !t.After(now) {
+		return time.Time{}, fmt.Errorf("%s is already past today", s)
+	}
+	return t, nil
+}
+
+// randomPassword returns a password that is easy to read off a handout
+func randomPassword() (string, error) {
+	b := make([]byte, fleetPasswordLength)
+	for i := range b {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(fleetPasswordChars))))
+		if err != nil {
+			return "", fmt.Errorf("could not generate password: %w", err)
+		}
+		b[i] = fleetPasswordChars[n.Int64()]
+	}
+	return string(b), nil
+}
+
+// maxGPUPrice returns the highest hourly price among the GPU types
+func maxGPUPrice(apiKey string, types []string) (float64, error) {
+	query := `{"query": "query { gpuTypes { id securePrice communityPrice } }"}`
+	req, err := http.NewRequest("POST", runpodGraphQLURL+"?api_key="+apiKey, strings.NewReader(query))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := newHTTPClient(10 * time.Second).Do(req)
+	if err != nil {
+		// The key is part of the URL in the error
+		return 0, fmt.Errorf("API request failed: %s", redact(err.Error()))
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	var result struct {
+		Data struct {
+			GPUTypes []struct {
+				ID             string  `json:"id"`
+				SecurePrice    float64 `json:"securePrice"`
+				CommunityPrice float64 `json:"communityPrice"`
+			} `json:"gpuTypes"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return 0, fmt.Errorf("could not parse response: %w", err)
+	}
+
+	highest := 0.0
+	for _, t := range types {
+		price := 0.0
+		for _, g := range result.Data.GPUTypes {
+			if g.ID == t {
+				price = max(g.SecurePrice, g.CommunityPrice)
+			}
+		}
+		if price == 0 {
+			return 0, fmt.Errorf("no price for %s", t)
+		}
+		highest = max(highest, price)
+	}
+	return highest, nil
+}
+
+// writeRoster writes roster.csv and a printable roster.html with one card
+// per seat
+func writeRoster(f *Fleet, dir string) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("could not create roster directory: %w", err)
+	}
+
+	f.mu.Lock()
+	seats := append([]FleetSeat{}, f.Seats...)
+	f.mu.Unlock()
+
+	csvFile, err := os.OpenFile(filepath.Join(dir, "roster.csv"), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	w := csv.NewWriter(csvFile)
+	w.Write([]string{"seat", "pod", "gpu", "status", "desktop_url", "files_url", "files_user", "password"})
+	for _, s := range seats {
+		w.Write([]string{s.label(), s.PodID, s.GPU, s.Status, s.DesktopURL, s.FilesURL, fileBrowserUser, s.Password})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		csvFile.Close()
+		return err
+	}
+	if err := csvFile.Close(); err != nil {
+		return err
+	}
+
+	type card struct {
+		FleetSeat
+		Label string
+		QR    template.HTML
+	}
+	var cards []card
+	for _, s := range seats {
+		if s.Status != seatReady {
+			continue
+		}
+		c := card{FleetSeat: s, Label: s.label()}
+		if qr, err := encodeQR(s.DesktopURL); err == nil {
+			c.QR = template.HTML(qr.svg(4))
+		}
+		cards = append(cards, c)
+	}
+
+	htmlFile, err := os.OpenFile(filepath.Join(dir, "roster.html"), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	err = rosterTemplate.Execute(htmlFile, map[string]interface{}{
+		"Fleet":     f.Name,
+		"EndsAt":    f.EndsAt.Format("Mon 2 Jan 15:04"),
+		"FilesUser": fileBrowserUser,
+		"Cards":     cards,
+	})
+	if cerr := htmlFile.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+var rosterTemplate = template.Must(template.New("roster").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Fleet}} - 3D Slicer seats</title>
+<style>
+body { font-family: sans-serif; margin: 1cm; }
+.card { display: inline-block; width: 8.5cm; margin: 0 0.4cm 0.6cm 0; padding: 0.4cm; border: 1px dashed #999; vertical-align: top; page-break-inside: avoid; }
+.card h2 { margin: 0 0 0.2cm; }
+.card svg { width: 4cm; height: 4cm; float: right; }
+.url { font-family: monospace; font-size: 8pt; word-break: break-all; }
+.pw { font-family: monospace; font-size: 14pt; font-weight: bold; }
+.note { font-size: 9pt; color: #555; clear: both; }
+</style>
+</head>
+<body>
+<h1>{{.Fleet}}</h1>
+<p>Desktops are available until {{.EndsAt}}. Download your work before then - the pods are deleted at the end.</p>
+{{range .Cards}}<div class="card">
+{{.QR}}
+<h2>Seat {{.Label}}</h2>
+<p>Password<br><span class="pw">{{.Password}}</span></p>
+<p>Desktop<br><span class="url">{{.DesktopURL}}</span></p>
+<p>Files ({{$.FilesUser}} / same password)<br><span class="url">{{.FilesURL}}</span></p>
+<p class="note">Scan the code or type the desktop address. Start file transfer from the desktop icon first.</p>
+</div>
+{{else}}<p>No seats are ready.</p>
+{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,306 @@
+// Copyright (c) 2025-2026 Mik Gangal
+// Licensed under CC BY-NC-SA 4.0 - https://creativecommons.org/licenses/by-nc-sa/4.0/
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Every launch appends its phase timings to sessions.jsonl in the state
+// directory. The stats subcommand summarises them per GPU type and data
+// center, to see where the startup time goes.
+
+const (
+	historyFileName    = "sessions.jsonl"
+	phaseDesktopReady  = "Desktop ready"
+	phaseFileBrowser   = "File Browser ready"
+	unknownDataCenter  = "unknown"
+	statsPhaseColWidth = 22
+)
+
+// statsPhases is the order phases are shown in
+var statsPhases = []string{
+	"Waiting for GPU",
+	"Pulling image",
+	"Starting services",
+	"Configuring network",
+	"Running",
+	phaseDesktopReady,
+	phaseFileBrowser,
+}
+
+// PhaseTiming is one completed launch phase
+type PhaseTiming struct {
+	Name        string    `json:"name"`
+	At          time.Time `json:"at"`
+	ElapsedSec  float64   `json:"elapsedSec"`  // since launch
+	DurationSec float64   `json:"durationSec"` // since the previous phase ended
+}
+
+// SessionTiming is one line of sessions.jsonl
+type SessionTiming struct {
+	PodID      string        `json:"podId"`
+	Start      time.Time     `json:"start"`
+	Profile    string        `json:"profile"`
+	Case       string        `json:"case,omitempty"`
+	GPU        string        `json:"gpu"`
+	DataCenter string        `json:"dataCenter"`
+	ReadySec   float64       `json:"readySec,omitempty"` // 0 = desktop never came up
+	Replaced   string        `json:"replaced,omitempty"` // why the pod was given up for another attempt
+	Phases     []PhaseTiming `json:"phases"`
+}
+
+// sessionHistory collects the timings of the current launch from events
+type sessionHistory struct {
+	mu      sync.Mutex
+	timing  SessionTiming
+	lastEnd float64
+	saved   bool
+}
+
+func newSessionHistory(profile, caseID string) *sessionHistory {
+	return &sessionHistory{timing: SessionTiming{Profile: profile, Case: caseID}}
+}
+
+func (h *sessionHistory) handle(ev Event) {
+	h.mu.Lock()
+	switch ev.Type {
+	case evLaunching:
+		h.timing.Start = ev.Time
+	case evCreated:
+		h.timing.PodID, h.timing.GPU, h.timing.DataCenter = ev.PodID, ev.GPU, ev.DataCenter
+	case evPhaseDone:
+		h.addPhase(ev.Phase, ev)
+	case evReady:
+		// evReady also comes when the desktop timed out (--on-not-ready=continue)
+		if n := len(h.timing.Phases); n > 0 && h.timing.Phases[n-1].Name == phaseDesktopReady {
+			h.timing.ReadySec = ev.ElapsedSec
+		}
+	case evFileBrowserReady:
+		h.addPhase(phaseFileBrowser, ev)
+	case evRetry:
+		h.timing.Replaced = ev.Message
+	}
+	h.mu.Unlock()
+
+	// A replaced pod gets its own record; the next attempt starts a new one
+	if ev.Type == evRetry {
+		h.save()
+		h.mu.Lock()
+		h.timing = SessionTiming{Start: ev.Time, Profile: h.timing.Profile, Case: h.timing.Case}
+		h.lastEnd = ev.ElapsedSec
+		h.saved = false
+		h.mu.Unlock()
+		return
+	}
+
+	// The launch is over once File Browser answered or gave up, or the
+	// pod goes away before that
+	switch ev.Type {
+	case evFileBrowserReady, evFileBrowserMissing, evTerminating:
+		h.save()
+	}
+}
+
+func (h *sessionHistory) addPhase(name string, ev Event) {
+	h.timing.Phases = append(h.timing.Phases, PhaseTiming{
+		Name:        name,
+		At:          ev.Time,
+		ElapsedSec:  ev.ElapsedSec,
+		DurationSec: ev.ElapsedSec - h.lastEnd,
+	})
+	h.lastEnd = ev.ElapsedSec
+}
+
+// save appends the session to sessions.jsonl, once. Launches that never
+// created a pod are not recorded.
+func (h *sessionHistory) save() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.saved || h.timing.PodID == "" {
+		return
+	}
+	h.saved = true
+	if h.timing.DataCenter == "" {
+		h.timing.DataCenter = unknownDataCenter
+	}
+
+	if err := appendSessionTiming(h.timing); err != nil {
+		logEvent(levelWarn, "Could not record launch timings: %v", err)
+	}
+}
+
+func historyPath() (string, error) {
+	dir, err := getStateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, historyFileName), nil
+}
+
+func appendSessionTiming(t SessionTiming) error {
+	path, err := historyPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// loadSessionTimings reads sessions.jsonl, skipping lines it can't parse
+func loadSessionTimings() ([]SessionTiming, error) {
+	path, err := historyPath()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var timings []SessionTiming
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var t SessionTiming
+		if json.Unmarshal(scanner.Bytes(), &t) == nil {
+			timings = append(timings, t)
+		}
+	}
+	return timings, scanner.Err()
+}
+
+// runStats prints median and p90 per phase by GPU type and data center
+func runStats(args []string) int {
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	days := fs.Int("days", 0, "only sessions from the last N days (0 = all)")
+	gpu := fs.String("gpu", "", "only sessions on this GPU type")
+	dataCenter := fs.String("dc", "", "only sessions in this data center")
+	caseID := fs.String("case", "", "only sessions of this case")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	timings, err := loadSessionTimings()
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitError
+	}
+
+	var selected []SessionTiming
+	for _, t := range timings {
+		if *days > 0 && time.Since(t.Start) > time.Duration(*days)*24*time.Hour {
+			continue
+		}
+		if (*gpu != "" && t.GPU != *gpu) || (*dataCenter != "" && t.DataCenter != *dataCenter) || (*caseID != "" && t.Case != *caseID) {
+			continue
+		}
+		selected = append(selected, t)
+	}
+	if len(selected) == 0 {
+		fmt.Println("No launches recorded yet. Timings are saved to " + historyFileName + " after each launch.")
+		return exitOK
+	}
+
+	fmt.Printf("Launch timings from %d session(s)\n", len(selected))
+	printStatsGroup("All launches", selected)
+	printStatsBy("GPU type", selected, func(t SessionTiming) string { return t.GPU })
+	printStatsBy("Data center", selected, func(t SessionTiming) string { return t.DataCenter })
+	return exitOK
+}
+
+func printStatsBy(title string, timings []SessionTiming, key func(SessionTiming) string) {
+	groups := make(map[string][]SessionTiming)
+	for _, t := range timings {
+		groups[key(t)] = append(groups[key(t)], t)
+	}
+	if len(groups) < 2 {
+		return // same as "All launches"
+	}
+
+	fmt.Printf("\nBy %s\n", title)
+	for _, name := range sortedKeys(groups) {
+		printStatsGroup(name, groups[name])
+	}
+}
+
+func printStatsGroup(name string, timings []SessionTiming) {
+	fmt.Printf("\n  %s%s%s (%d)\n", colorCyan, name, colorReset, len(timings))
+	fmt.Printf("  %s%-*s %5s %9s %9s%s\n", colorDim, statsPhaseColWidth, "Phase", "n", "median", "p90", colorReset)
+
+	for _, phase := range statsPhases {
+		var values []float64
+		for _, t := range timings {
+			for _, p := range t.Phases {
+				if p.Name == phase {
+					values = append(values, p.DurationSec)
+				}
+			}
+		}
+		printStatsRow(phase, values)
+	}
+
+	var ready []float64
+	for _, t := range timings {
+		if t.ReadySec > 0 {
+			ready = append(ready, t.ReadySec)
+		}
+	}
+	printStatsRow("Total to desktop", ready)
+
+	replaced := 0
+	for _, t := range timings {
+		if t.Replaced != "" {
+			replaced++
+		}
+	}
+	if replaced > 0 {
+		fmt.Printf("  %s%d of these pods were replaced (stuck in the GPU queue or image pull)%s\n", colorDim, replaced, colorReset)
+	}
+}
+
+func printStatsRow(label string, values []float64) {
+	if len(values) == 0 {
+		return
+	}
+	sort.Float64s(values)
+	fmt.Printf("  %-*s %5d %9s %9s\n", statsPhaseColWidth, label, len(values),
+		formatDuration(secondsDuration(percentile(values, 50))),
+		formatDuration(secondsDuration(percentile(values, 90))))
+}
+
+// percentile uses the nearest-rank method on sorted values
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func secondsDuration(sec float64) time.Duration {
+	return time.Duration(sec * float64(time.Second))
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2025-2026 Mik Gangal
+// Licensed under CC BY-NC-SA 4.0 - https://creativecommons.org/licenses/by-nc-sa/4.0/
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+)
+
+// jsonSink writes events as newline-delimited JSON (--json) so scripts and
+// schedulers can follow a session. Progress ticks are collapsed to one line
+// per phase change.
+type jsonSink struct {
+	mu        sync.Mutex
+	enc       *json.Encoder
+	lastPhase string
+}
+
+func newJSONSink(w io.Writer) *jsonSink {
+	return &jsonSink{enc: json.NewEncoder(w)}
+}
+
+func (j *jsonSink) handle(ev Event) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if ev.Type == evProgress {
+		if ev.Phase == j.lastPhase {
+			return
+		}
+		j.lastPhase = ev.Phase
+	}
+	j.enc.Encode(ev)
+}
+
+// runHeadless keeps the session alive without a UI. It returns when the
+// session budget runs out, the session is ended from the web dashboard or,
+// if readStdin is set, when a line is read from stdin. Signals are handled
+// by setupSignalHandler.
+func runHeadless(s *session, readStdin bool) bool {
+	enter := make(chan struct{})
+	if readStdin {
+		go func() {
+			// A closed stdin (e.g. </dev/null) must not end the session
+			if _, err := bufio.NewReader(os.Stdin).ReadBytes('\n'); err == nil {
+				close(enter)
+			}
+		}()
+	}
+
+	select {
+	case <-enter:
+	case <-s.expired:
+	case <-s.quit:
+	}
+	return false
+}
@@ -0,0 +1,191 @@
+// Copyright (c) 2025-2026 Mik Gangal
+// Licensed under CC BY-NC-SA 4.0 - https://creativecommons.org/licenses/by-nc-sa/4.0/
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// API keys are checked when they are entered or loaded, before anything
+// is launched: a GraphQL "myself" query shows whose key it is. RunPod has
+// no read-only way to ask what a key may do, so permissions are only
+// probed when a new key is entered: a create-pod request RunPod must reject
+// as invalid. A 401/403 means read-only; any other rejection proves nothing,
+// since RunPod may validate the body before the permissions. A read-only key
+// that gets through is caught by the 401/403 of the launch itself.
+
+// permissionProbe is a create-pod body RunPod rejects (no image or
+// template, negative GPU count), so the probe never starts anything
+const permissionProbe = `{"name":"slicer-key-check","gpuCount":-1}`
+
+var (
+	// errKeyInvalid: RunPod does not know the key
+	errKeyInvalid = errors.New("RunPod rejected the API key - it may be mistyped, cut off when pasting, or deleted in the RunPod console (Settings > API Keys)")
+
+	// errKeyReadOnly: the key can read the account but not create pods
+	errKeyReadOnly = errors.New("the API key is read-only - it can see the account but not create pods; create a key with 'All' (Read/Write) permissions at https://www.runpod.io/console/user/settings")
+)
+
+// KeyCheck is what RunPod says about an API key
+type KeyCheck struct {
+	Email     string
+	Balance   float64
+	CostPerHr float64
+}
+
+var (
+	keyChecksMu sync.Mutex
+	keyChecks   = make(map[string]*KeyCheck)
+)
+
+// checkAPIKey asks RunPod whose key it is. It returns errKeyInvalid for
+// keys RunPod doesn't know, and other errors when RunPod could not be
+// asked. Results are kept for the rest of the run.
+func checkAPIKey(apiKey string) (*KeyCheck, error) {
+	keyChecksMu.Lock()
+	cached := keyChecks[apiKey]
+	keyChecksMu.Unlock()
+	if cached != nil {
+		return cached, nil
+	}
+
+	check, err := queryKeyOwner(apiKey)
+	if err != nil {
+		return nil, err
+	}
+	keyChecksMu.Lock()
+	keyChecks[apiKey] = check
+	keyChecksMu.Unlock()
+	return check, nil
+}
+
+// checkNewAPIKey is checkAPIKey for a key that is being entered: it also
+// returns errKeyReadOnly when the permission probe shows the key can't
+// create pods
+func checkNewAPIKey(apiKey string) (*KeyCheck, error) {
+	check, err := checkAPIKey(apiKey)
+	if err != nil {
+		return check, err
+	}
+	readOnly, err := probeReadOnly(apiKey)
+	if err != nil {
+		slog.Warn("pod permission check failed", "error", err)
+	}
+	if readOnly {
+		return check, errKeyReadOnly
+	}
+	return check, nil
+}
+
+// queryKeyOwner reads the account behind a key
+func queryKeyOwner(apiKey string) (*KeyCheck, error) {
+	query := `{"query": "query { myself { id email clientBalance currentSpendPerHr } }"}`
+	req, err := http.NewRequest("POST", runpodGraphQLURL+"?api_key="+apiKey, strings.NewReader(query))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := newHTTPClient(10 * time.Second).Do(req)
+	if err != nil {
+		// The key is part of the URL in the error
+		return nil, fmt.Errorf("could not reach RunPod: %s", redact(err.Error()))
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return nil, errKeyInvalid
+	}
+
+	var result struct {
+		Data struct {
+			Myself *struct {
+				ID                string  `json:"id"`
+				Email             string  `json:"email"`
+				ClientBalance     float64 `json:"clientBalance"`
+				CurrentSpendPerHr float64 `json:"currentSpendPerHr"`
+			} `json:"myself"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("unexpected answer from RunPod (%d): %s", resp.StatusCode, truncate(string(body), 200))
+	}
+	for _, e := range result.Errors {
+		msg := strings.ToLower(e.Message)
+		if strings.Contains(msg, "unauthorized") || strings.Contains(msg, "api key") || strings.Contains(msg, "authenticat") {
+			return nil, errKeyInvalid
+		}
+	}
+	if len(result.Errors) > 0 {
+		return nil, fmt.Errorf("RunPod: %s", result.Errors[0].Message)
+	}
+	me := result.Data.Myself
+	if me == nil || me.ID == "" {
+		return nil, errKeyInvalid
+	}
+	return &KeyCheck{Email: me.Email, Balance: me.ClientBalance, CostPerHr: me.CurrentSpendPerHr}, nil
+}
+
+// probeReadOnly sends the invalid create-pod request and reports whether
+// RunPod turned it down for lack of permission
+func probeReadOnly(apiKey string) (bool, error) {
+	req, err := http.NewRequest("POST", runpodAPIURL, strings.NewReader(permissionProbe))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
+
+	resp, err := newHTTPClient(15 * time.Second).Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return true, nil
+	case resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusUnprocessableEntity:
+		// Rejected as invalid - which may have been checked first
+		return false, nil
+	case resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusCreated:
+		// Should never happen; don't leave a pod behind if it did
+		var pod PodResponse
+		if json.Unmarshal(body, &pod) == nil && pod.ID != "" {
+			if err := deletePod(apiKey, pod.ID); err != nil {
+				reportTerminationFailure(apiKey, pod.ID, err)
+			}
+		}
+		return false, nil
+	}
+	return false, fmt.Errorf("unexpected answer to the permission check (%d): %s", resp.StatusCode, truncate(string(body), 200))
+}
+
+// keyProblem reports whether err means the key itself can't be used, as
+// opposed to RunPod not answering
+func keyProblem(err error) bool {
+	return errors.Is(err, errKeyInvalid) || errors.Is(err, errKeyReadOnly)
+}
+
+// describeKey is the one-line summary shown after a check
+func describeKey(account string, check *KeyCheck) string {
+	s := fmt.Sprintf("API key OK: %s, balance $%.2f", check.Email, check.Balance)
+	if account != "" {
+		s = fmt.Sprintf("API key OK (account %s): %s, balance $%.2f", account, check.Email, check.Balance)
+	}
+	return s
+}
@@ -0,0 +1,323 @@
+// Copyright (c) 2025-2026 Mik Gangal
+// Licensed under CC BY-NC-SA 4.0 - https://creativecommons.org/licenses/by-nc-sa/4.0/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Every session writes a structured log to ~/.slicer-launcher/logs. API calls
+// are logged by the HTTP transport, everything else arrives as session events.
+// Secrets are redacted before anything reaches the file or the console.
+
+const (
+	logDirName     = "logs"
+	maxLogSize     = 5 << 20 // roll over to <name>.1 beyond this
+	maxSessionLogs = 20
+	maxLoggedBody  = 4 << 10
+)
+
+// sessionLogPath is the current session's log file ("" if logging is off)
+var sessionLogPath string
+
+func getLogDir() (string, error) {
+	dir, err := getStateDir()
+	if err != nil {
+		return "", err
+	}
+	dir = filepath.Join(dir, logDirName)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("could not create log directory: %w", err)
+	}
+	return dir, nil
+}
+
+// initLogging opens this session's log file and installs the default slog
+// logger. With verbose set the log is mirrored to stderr. The returned
+// function closes the file.
+func initLogging(verbose bool) (func(), error) {
+	var out io.Writer = io.Discard
+	if verbose {
+		out = os.Stderr
+	}
+	closeLog := func() {}
+
+	dir, err := getLogDir()
+	if err == nil {
+		pruneSessionLogs(dir)
+		path := filepath.Join(dir, "session-"+time.Now().Format("20060102-150405")+".log")
+		var rf *rotatingFile
+		if rf, err = openRotatingFile(path, maxLogSize); err == nil {
+			sessionLogPath = path
+			closeLog = rf.Close
+			if verbose {
+				out = io.MultiWriter(rf, os.Stderr)
+			} else {
+				out = rf
+			}
+		}
+	}
+
+	handler := slog.NewTextHandler(&redactWriter{w: out}, &slog.HandlerOptions{Level: slog.LevelDebug})
+	slog.SetDefault(slog.New(handler))
+	return closeLog, err
+}
+
+// pruneSessionLogs keeps only the newest session logs
+func pruneSessionLogs(dir string) {
+	logs, _ := filepath.Glob(filepath.Join(dir, "session-*.log"))
+	if len(logs) < maxSessionLogs {
+		return
+	}
+	sort.Strings(logs) // timestamped names sort oldest first
+	for _, p := range logs[:len(logs)-maxSessionLogs+1] {
+		os.Remove(p)
+		os.Remove(p + ".1")
+	}
+}
+
+// rotatingFile is a log file that is moved to <path>.1 once it grows past maxSize
+type rotatingFile struct {
+	mu      sync.Mutex
+	path    string
+	maxSize int64
+	f       *os.File
+	size    int64
+}
+
+func openRotatingFile(path string, maxSize int64) (*rotatingFile, error) {
+	rf := &rotatingFile{path: path, maxSize: maxSize}
+	if err := rf.open(); err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
+func (rf *rotatingFile) open() error {
+	f, err := os.OpenFile(rf.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("could not open log file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("could not open log file: %w", err)
+	}
+	rf.f, rf.size = f, info.Size()
+	return nil
+}
+
+func (rf *rotatingFile) Write(p []byte) (int, error) {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	if rf.f == nil {
+		return 0, os.ErrClosed
+	}
+	if rf.size > 0 && rf.size+int64(len(p)) > rf.maxSize {
+		rf.f.Close()
+		os.Rename(rf.path, rf.path+".1")
+		if err := rf.open(); err != nil {
+			rf.f = nil
+			return 0, err
+		}
+	}
+	n, err := rf.f.Write(p)
+	rf.size += int64(n)
+	return n, err
+}
+
+func (rf *rotatingFile) Close() {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+	if rf.f != nil {
+		rf.f.Close()
+		rf.f = nil
+	}
+}
+
+var (
+	secretsMu sync.RWMutex
+	secrets   []string
+
+	// Credentials that can show up in URLs, headers and request bodies
+	secretPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(api_key=)[^&\s"]+`),
+		regexp.MustCompile(`(Bearer )[^\s"]+`),
+		regexp.MustCompile(`("password"\s*:\s*")[^"]*`),
+		regexp.MustCompile(`()rpa_[A-Za-z0-9]+`),
+	}
+)
+
+// addSecret registers a value that must never appear in logs
+func addSecret(s string) {
+	if len(s) < 6 {
+		return
+	}
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	secrets = append(secrets, s)
+}
+
+// redact replaces registered secrets and known credential patterns
+func redact(s string) string {
+	secretsMu.RLock()
+	for _, secret := range secrets {
+		s = strings.ReplaceAll(s, secret, "[REDACTED]")
+	}
+	secretsMu.RUnlock()
+
+	for _, re := range secretPatterns {
+		s = re.ReplaceAllString(s, "${1}[REDACTED]")
+	}
+	return s
+}
+
+// redactWriter redacts each write (slog writes one record per call)
+type redactWriter struct {
+	w io.Writer
+}
+
+func (r *redactWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(r.w, redact(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// loggingTransport logs every HTTP request the launcher makes
+type loggingTransport struct {
+	base http.RoundTripper
+}
+
+// newHTTPClient returns a client whose requests end up in the session log
+func newHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: &loggingTransport{base: http.DefaultTransport},
+	}
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	attrs := []any{"method", req.Method, "url", req.URL.String()}
+	if req.GetBody != nil && isJSON(req.Header.Get("Content-Type")) && req.ContentLength <= maxLoggedBody {
+		if body, err := req.GetBody(); err == nil {
+			data, _ := io.ReadAll(body)
+			body.Close()
+			attrs = append(attrs, "request", string(data))
+		}
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	elapsed := time.Since(start)
+	attrs = append(attrs, "duration", elapsed.Round(time.Millisecond))
+	if err != nil {
+		recordAPICall(req, elapsed, 0, err)
+		slog.Warn("http request failed", append(attrs, "error", err)...)
+		return nil, err
+	}
+	recordAPICall(req, elapsed, resp.StatusCode, nil)
+	attrs = append(attrs, "status", resp.StatusCode)
+
+	// Keep small JSON responses; the body is put back for the caller
+	if isJSON(resp.Header.Get("Content-Type")) && resp.ContentLength <= maxLoggedBody {
+		data, readErr := io.ReadAll(io.LimitReader(resp.Body, maxLoggedBody+1))
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(data), resp.Body), resp.Body}
+		if readErr == nil && len(data) <= maxLoggedBody {
+			attrs = append(attrs, "response", string(data))
+		}
+	}
+
+	level := slog.LevelDebug
+	if resp.StatusCode >= 400 {
+		level = slog.LevelWarn
+	}
+	slog.Log(req.Context(), level, "http request", attrs...)
+	return resp, nil
+}
+
+func isJSON(contentType string) bool {
+	return strings.Contains(contentType, "json")
+}
+
+// slogSink writes session events to the structured log
+type slogSink struct {
+	mu        sync.Mutex
+	lastPhase string
+}
+
+func (l *slogSink) handle(ev Event) {
+	// Only finished or failed transfers, not every progress tick
+	if t := ev.Transfer; t != nil && !t.Done && t.Error == "" {
+		return
+	}
+	if ev.Type == evProgress {
+		l.mu.Lock()
+		same := ev.Phase == l.lastPhase
+		l.lastPhase = ev.Phase
+		l.mu.Unlock()
+		if same {
+			return
+		}
+	}
+
+	level := slog.LevelInfo
+	switch {
+	case ev.Level == levelWarn:
+		level = slog.LevelWarn
+	case ev.Level == levelError, ev.Type == evTerminateFailed:
+		level = slog.LevelError
+	}
+
+	attrs := []any{"event", ev.Type}
+	add := func(key, value string) {
+		if value != "" {
+			attrs = append(attrs, key, value)
+		}
+	}
+	add("pod", ev.PodID)
+	add("gpu", ev.GPU)
+	add("phase", ev.Phase)
+	add("detail", ev.Detail)
+	add("desktop", ev.DesktopURL)
+	add("files", ev.FilesURL)
+	if ev.ElapsedSec > 0 {
+		attrs = append(attrs, "elapsed", ev.Elapsed().Round(time.Second))
+	}
+	if ev.Balance > 0 || ev.CostPerHr > 0 {
+		attrs = append(attrs, "balance", ev.Balance, "costPerHr", ev.CostPerHr)
+	}
+	if ev.Deadline != nil {
+		attrs = append(attrs, "deadline", ev.Deadline.Format(time.RFC3339))
+	}
+	if t := ev.Transfer; t != nil {
+		attrs = append(attrs, "transfer", t.Name, "direction", t.Direction, "bytes", t.Bytes)
+		if t.Error != "" {
+			level = slog.LevelError
+			attrs = append(attrs, "error", t.Error)
+		}
+	}
+
+	msg := ev.Message
+	if msg == "" {
+		msg = ev.Type
+	}
+	slog.Log(context.Background(), level, msg, attrs...)
+}
@@ -7,8 +7,11 @@ import (
 	"bufio"
 	"bytes"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/exec"
@@ -16,15 +19,24 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 )
 
 // Global state for cleanup on exit
 var (
-	activePodID  string
-	activeAPIKey string
-	launchStart  time.Time
+	activePodID     string
+	activeAPIKey    string
+	activeHeartbeat string
+	launchStart     time.Time
+
+	// closeDashboard restores the terminal when the TUI is active
+	closeDashboard func()
+
+	// exitHooks run before the launcher exits, last registered first
+	exitHooksMu sync.Mutex
+	exitHooks   []func()
 )
 
 const (
@@ -55,11 +67,13 @@ const (
 // PodRequest represents the RunPod API request body
 // Ports are inherited from the template
 type PodRequest struct {
-	Name            string   `json:"name"`
-	TemplateID      string   `json:"templateId"`
-	NetworkVolumeID string   `json:"networkVolumeId"`
-	GPUTypeIDs      []string `json:"gpuTypeIds"`
-	GPUCount        int      `json:"gpuCount"`
+	Name            string            `json:"name"`
+	TemplateID      string            `json:"templateId"`
+	NetworkVolumeID string            `json:"networkVolumeId,omitempty"`
+	GPUTypeIDs      []string          `json:"gpuTypeIds"`
+	DataCenterIDs   []string          `json:"dataCenterIds,omitempty"`
+	GPUCount        int               `json:"gpuCount"`
+	Env             map[string]string `json:"env,omitempty"`
 }
 
 // PodResponse represents the RunPod API response
@@ -68,11 +82,13 @@ type PodResponse struct {
 	Name            string `json:"name"`
 	DesiredStatus   string `json:"desiredStatus"`
 	ImageName       string `json:"imageName"`
+	CostPerHr       float64 `json:"costPerHr"`
 	Machine         Machine `json:"machine"`
 }
 
 type Machine struct {
 	GpuDisplayName string `json:"gpuDisplayName"`
+	DataCenterID   string `json:"dataCenterId"`
 }
 
 // ErrorResponse represents an error from the API
@@ -80,143 +96,733 @@ type ErrorResponse struct {
 	Error string `json:"error"`
 }
 
+// Exit codes of a normal launch, for scripts and schedulers
+const (
+	exitOK           = 0
+	exitError        = 1 // includes a pod whose termination could not be confirmed
+	exitUsage        = 2 // bad flags or no API key
+	exitLaunchFailed = 3 // the pod could not be created
+	exitNotReady     = 4 // the desktop never came up (--on-not-ready=terminate)
+	exitInterrupted  = 5 // the spot pod was taken back and not relaunched
+)
+
+// What to do when the desktop does not come up in time
+const (
+	notReadyContinue  = "continue"
+	notReadyTerminate = "terminate"
+)
+
+// apiKeyEnv is read instead of prompting when prompts are not allowed
+const apiKeyEnv = "RUNPOD_API_KEY"
+
+// launchOptions holds the command-line flags for a normal launch
+type launchOptions struct {
+	tui              bool
+	watchdog         bool
+	deadman          time.Duration
+	maxHours         float64
+	heartbeatTimeout time.Duration
+	json             bool
+	nonInteractive   bool
+	noBrowser        bool
+	detach           bool
+	onNotReady       string
+	verbose          bool
+	web              bool
+	metrics          string
+	queueTimeout     time.Duration
+	pullTimeout      time.Duration
+	launchTimeout    time.Duration
+	noNotify         bool
+	profile          string
+	spot             bool
+	bid              float64
+	onInterrupt      string
+	caseID           string
+	notes            string
+	account          string
+}
+
+// interactive is false when the launcher must never prompt (--json or --non-interactive)
+var interactive = true
+
+func parseLaunchFlags() launchOptions {
+	var opts launchOptions
+	flag.BoolVar(&opts.tui, "tui", false,
+		"show a full-screen dashboard with key bindings instead of scrolling output")
+	flag.BoolVar(&opts.watchdog, "watchdog", false,
+		"start a detached watchdog that terminates the pod if the launcher dies")
+	flag.DurationVar(&opts.deadman, "deadman", defaultDeadman,
+		"watchdog dead-man timer: terminate the pod after this long without a heartbeat")
+	flag.Float64Var(&opts.maxHours, "max-hours", defaultMaxHours,
+		"pod shuts itself down after this many hours (0 = no limit)")
+	flag.DurationVar(&opts.heartbeatTimeout, "heartbeat-timeout", defaultHeartbeatTimeout,
+		"pod shuts itself down when the launcher's heartbeats stop for this long (0 = off)")
+	flag.BoolVar(&opts.json, "json", false,
+		"write newline-delimited JSON events to stdout instead of text (implies no prompts)")
+	flag.BoolVar(&opts.nonInteractive, "non-interactive", false,
+		"never prompt or wait for keys; the API key comes from "+apiKeyEnv+" or the saved key")
+	flag.BoolVar(&opts.noBrowser, "no-browser", false,
+		"don't open the desktop and File Browser in a browser")
+	flag.BoolVar(&opts.detach, "detach", false,
+		"exit once the pod is ready and leave it running (only --max-hours stops it)")
+	flag.StringVar(&opts.onNotReady, "on-not-ready", notReadyContinue,
+		"what to do if the desktop doesn't come up in time: continue or terminate")
+	flag.DurationVar(&opts.queueTimeout, "queue-timeout", defaultQueueTimeout,
+		"replace a pod waiting this long for a GPU, on the next GPU type or data center (0 = wait)")
+	flag.DurationVar(&opts.pullTimeout, "pull-timeout", defaultPullTimeout,
+		"recreate a pod that spends this long pulling the image (0 = wait)")
+	flag.DurationVar(&opts.launchTimeout, "launch-timeout", defaultLaunchTimeout,
+		"give up on the desktop after this long, across all retries")
+	flag.BoolVar(&opts.web, "web", false,
+		"serve a dashboard page on localhost and open it in the browser")
+	flag.StringVar(&opts.metrics, "metrics", "",
+		"serve Prometheus metrics for the session on this address (e.g. 127.0.0.1:9464)")
+	flag.StringVar(&opts.profile, "profile", defaultProfileName,
+		"launch profile from settings.json (template, network volume, GPU types)")
+	flag.BoolVar(&opts.spot, "spot", false,
+		"launch an interruptible (spot) pod, as the profile's \"spot\" setting")
+	flag.Float64Var(&opts.bid, "bid", 0,
+		"spot bid per GPU in USD/hr (default: the profile's, or the current minimum bid)")
+	flag.StringVar(&opts.onInterrupt, "on-interrupt", "",
+		"when RunPod takes the spot pod back: end, on-demand or next-gpu (default: the profile's, or end)")
+	flag.StringVar(&opts.account, "account", selectedAccount,
+		"saved RunPod account to launch on (see the accounts subcommand; default: the profile's or the default account)")
+	flag.StringVar(&opts.caseID, "case", "",
+		"case ID for the pod name, the cost ledger and downloaded exports (asks for notes)")
+	flag.StringVar(&opts.notes, "notes", "",
+		"notes about the case, recorded with its cost (instead of asking)")
+	flag.BoolVar(&opts.noNotify, "no-notify", false,
+		"no desktop notifications (pick individual ones in settings.json)")
+	flag.BoolVar(&opts.verbose, "verbose", false,
+		"mirror the session log (API calls, phases, transfers) to stderr")
+	flag.Parse()
+
+	if opts.deadman < minDeadman {
+		opts.deadman = minDeadman
+	}
+	if opts.nonInteractive {
+		opts.noBrowser = true
+	}
+	if opts.detach {
+		// Nobody sends heartbeats after the launcher exits
+		opts.heartbeatTimeout = 0
+		opts.watchdog = false
+	}
+	return opts
+}
+
+func (o launchOptions) validate() error {
+	if o.onNotReady != notReadyContinue && o.onNotReady != notReadyTerminate {
+		return fmt.Errorf("--on-not-ready must be %q or %q", notReadyContinue, notReadyTerminate)
+	}
+	if o.detach && o.maxHours <= 0 {
+		return fmt.Errorf("--detach needs a --max-hours limit")
+	}
+	if o.launchTimeout <= 0 {
+		return fmt.Errorf("--launch-timeout must be positive")
+	}
+	if o.bid < 0 {
+		return fmt.Errorf("--bid must not be negative")
+	}
+	if err := validOnInterrupt(o.onInterrupt); err != nil {
+		return fmt.Errorf("--on-interrupt: %w", err)
+	}
+	if o.account != "" {
+		if err := validAccountName(o.account); err != nil {
+			return fmt.Errorf("--account: %w", err)
+		}
+	}
+	if o.caseID != "" {
+		if err := validCaseID(o.caseID); err != nil {
+			return fmt.Errorf("--case: %w", err)
+		}
+	}
+	return nil
+}
+
+// applySpot lets the spot flags override the profile
+func (o launchOptions) applySpot(p *Profile) {
+	if o.spot || o.bid > 0 {
+		p.Spot = true
+	}
+	if o.bid > 0 {
+		p.BidPerGPU = o.bid
+	}
+	if o.onInterrupt != "" {
+		p.OnInterrupt = o.onInterrupt
+	}
+}
+
+// launchLimits returns the phase timeouts for a launch started at start
+func (o launchOptions) launchLimits(start time.Time) launchLimits {
+	return launchLimits{queue: o.queueTimeout, pull: o.pullTimeout, deadline: start.Add(o.launchTimeout)}
+}
+
 func main() {
 	// Enable ANSI colors on Windows
 	enableWindowsANSI()
 
-	fmt.Println("╔════════════════════════════════════════════════════════════╗")
-	fmt.Println("║           3D Slicer RunPod Launcher                        ║")
-	fmt.Println("╚════════════════════════════════════════════════════════════╝")
-	fmt.Println()
+	// Only launches write a session log (see initLogging)
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	// --account in front of a subcommand picks the key it uses
+	os.Args = takeAccountFlag(os.Args)
+
+	// Internal subcommands
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "watchdog":
+			os.Exit(runWatchdog(os.Args[2:]))
+		case "support-bundle":
+			os.Exit(runSupportBundle(os.Args[2:]))
+		case "webhook":
+			os.Exit(runWebhookCommand(os.Args[2:]))
+		case "stats":
+			os.Exit(runStats(os.Args[2:]))
+		case "fleet":
+			exit(runFleetCommand(os.Args[2:]))
+		case "list":
+			os.Exit(runList(os.Args[2:]))
+		case "terminate":
+			os.Exit(runTerminate(os.Args[2:]))
+		case "stop":
+			os.Exit(runStop(os.Args[2:]))
+		case "volume":
+			os.Exit(runVolumeCommand(os.Args[2:]))
+		case "schedule":
+			exit(runScheduleCommand(os.Args[2:]))
+		case "batch":
+			exit(runBatchCommand(os.Args[2:]))
+		case "export":
+			os.Exit(runExportCommand(os.Args[2:]))
+		case "print":
+			os.Exit(runPrintCommand(os.Args[2:]))
+		case "check":
+			os.Exit(runCheckCommand(os.Args[2:]))
+		case "costs":
+			os.Exit(runCosts(os.Args[2:]))
+		case "accounts":
+			os.Exit(runAccountsCommand(os.Args[2:]))
+		}
+	}
+
+	opts := parseLaunchFlags()
+	interactive = !opts.json && !opts.nonInteractive
+
+	// Scripts get JSON events, people get the console output
+	var console *consoleUI
+	if opts.json {
+		subscribe(newJSONSink(os.Stdout).handle)
+	} else {
+		console = newConsoleUI()
+		subscribe(console.handle)
+	}
+
+	// The dashboard owns the screen, so it never gets the verbose mirror
+	closeLog, err := initLogging(opts.verbose && !opts.tui)
+	if err != nil {
+		logEvent(levelWarn, "Session log disabled: %v", err)
+	}
+	atExit(closeLog)
+	subscribe((&slogSink{}).handle)
+	slog.Info("launcher started", "os", runtime.GOOS, "arch", runtime.GOARCH, "args", strings.Join(os.Args[1:], " "))
+
+	if appSettings, err = loadSettings(); err != nil {
+		logEvent(levelWarn, "Using default settings: %v", err)
+	}
+	appSettings.addSettingsSecrets()
+	if !opts.noNotify {
+		subscribe((&desktopNotifier{settings: appSettings}).handle)
+	}
+
+	if err := opts.validate(); err != nil {
+		logEvent(levelError, "%v", err)
+		exit(exitUsage)
+	}
+	profile, err := appSettings.profile(opts.profile)
+	if err != nil {
+		logEvent(levelError, "%v", err)
+		exit(exitUsage)
+	}
+	opts.applySpot(&profile)
+	selectedAccount = opts.account
+	account, err := launchAccount(profile)
+	if err != nil {
+		logEvent(levelError, "%v", err)
+		exit(exitUsage)
+	}
+
+	// Team notifications; undelivered webhooks are kept for the next run
+	if len(appSettings.Webhooks) > 0 {
+		hooks := newWebhookSink(appSettings, profile.Name)
+		subscribe(hooks.handle)
+		atExit(func() { hooks.queue.flush(webhookFlushWait) })
+	}
+
+	// Phase timings for the stats subcommand
+	history := newSessionHistory(profile.Name, opts.caseID)
+	subscribe(history.handle)
+	atExit(history.save)
+
+	if console != nil {
+		fmt.Println("╔════════════════════════════════════════════════════════════╗")
+		fmt.Println("║           3D Slicer RunPod Launcher                        ║")
+		fmt.Println("╚════════════════════════════════════════════════════════════╝")
+		fmt.Println()
+	}
 
 	// Setup signal handler for cleanup on Ctrl+C or window close
 	setupSignalHandler()
 
 	// Get API key
-	apiKey, err := getAPIKey()
+	var apiKey string
+	if interactive {
+		account, apiKey, err = getAPIKey(account)
+	} else {
+		apiKey, err = getAPIKeyNoPrompt(account)
+	}
 	if err != nil {
-		fmt.Printf("Error getting API key: %v\n", err)
+		logEvent(levelError, "getting API key: %v", err)
 		waitForEnter()
-		os.Exit(1)
+		exit(exitUsage)
 	}
+	addSecret(apiKey)
 
-	// Show technical details in compact format
-	fmt.Println()
-	fmt.Printf("%s── Configuration ──────────────────────────────────────────────%s\n", colorDim, colorReset)
-	fmt.Printf("%sTemplate: %s │ Volume: %s │ GPU: %s%s\n",
-		colorDim, templateID, networkVolumeID, gpuTypes[0], colorReset)
-	fmt.Printf("%s───────────────────────────────────────────────────────────────%s\n", colorDim, colorReset)
-	fmt.Println()
+	// Find out now, not when creating the pod, if the key can't launch
+	check, err := checkAPIKey(apiKey)
+	switch {
+	case keyProblem(err):
+		logEvent(levelError, "%v", err)
+		waitForEnter()
+		exit(exitUsage)
+	case err != nil:
+		logEvent(levelWarn, "Could not check the API key: %v", err)
+	default:
+		logEvent(levelOK, "%s", describeKey(account, check))
+	}
 
-	// Start timing
-	launchStart = time.Now()
+	// Retry termination of pods a previous run could not confirm as gone
+	cleanupUnconfirmedPods(apiKey)
 
-	fmt.Println("Launching pod...")
-	podID, gpuName, err := launchPod(apiKey)
-	if err != nil {
-		fmt.Printf("Error launching pod: %v\n", err)
-		waitForEnter()
-		os.Exit(1)
+	// What each pod of this launch costs, traceable to the case
+	activeCase = opts.caseID
+	if activeCase != "" && opts.notes == "" && console != nil && interactive {
+		opts.notes = promptNotes(activeCase)
+	}
+	ledger := newCostLedger(profile.Name, opts.caseID, opts.notes)
+	subscribe(ledger.handle)
+	atExit(ledger.save)
+
+	// Resolve a volume picked by name and check its data center has GPUs
+	if err := prepareVolume(apiKey, &profile); err != nil {
+		logEvent(levelError, "%v", err)
+		waitForKey("Press Enter to exit...")
+		exit(exitUsage)
 	}
 
-	// Store for cleanup on exit
-	activeAPIKey = apiKey
-	activePodID = podID
+	// Don't start what the account can't pay for
+	if _, err := checkBalance(apiKey, profile, 1); err != nil {
+		logEvent(levelError, "Account %s: %v", accountLabel(account), err)
+		waitForKey("Press Enter to exit...")
+		exit(exitLaunchFailed)
+	}
+
+	exit(runSession(apiKey, profile, opts, console))
+}
+
+// atExit registers cleanup that must run however the launcher exits
+func atExit(fn func()) {
+	exitHooksMu.Lock()
+	defer exitHooksMu.Unlock()
+	exitHooks = append(exitHooks, fn)
+}
 
-	fmt.Printf("  %s✓%s Pod created: %s\n", colorGreen, colorReset, podID)
-	if gpuName != "" {
-		fmt.Printf("  %s✓%s GPU: %s\n", colorGreen, colorReset, gpuName)
+// exit runs the exit hooks and ends the process
+func exit(code int) {
+	slog.Info("launcher exiting", "code", code)
+	exitHooksMu.Lock()
+	hooks := exitHooks
+	exitHooks = nil
+	exitHooksMu.Unlock()
+
+	for i := len(hooks) - 1; i >= 0; i-- {
+		hooks[i]()
 	}
-	fmt.Println()
+	os.Exit(code)
+}
 
-	// Wait for pod to be ready with progress display
-	vncURL := fmt.Sprintf("https://%s-6080.proxy.runpod.net", podID)
-	_, tcpPorts, err := waitForPodReady(apiKey, podID, vncURL)
+// runSession launches a pod, waits until it is ready and keeps it running
+// until the user exits. It returns the process exit code. console is nil
+// when events go out as JSON.
+func runSession(apiKey string, profile Profile, opts launchOptions, console *consoleUI) int {
+	// Pod-side auto-termination settings
+	guard, err := newPodGuard(time.Duration(opts.maxHours*float64(time.Hour)), opts.heartbeatTimeout)
 	if err != nil {
-		fmt.Printf("Warning: %v\n", err)
-		fmt.Println("Opening browser anyway...")
+		logEvent(levelError, "%v", err)
+		waitForEnter()
+		return exitError
 	}
 
-	// Calculate and display load time
-	loadDuration := time.Since(launchStart)
-	fmt.Printf("\n%s✓ Ready in %s%s\n", colorGreen, formatDuration(loadDuration), colorReset)
+	// Optional browser dashboard, fed by the same events as the console
+	var web *webUI
+	if opts.web {
+		if web, err = startWebUI(); err != nil {
+			logEvent(levelWarn, "Web dashboard unavailable: %v", err)
+		} else {
+			addSecret(web.token)
+			subscribe(web.handle)
+			defer web.close()
+			logEvent(levelInfo, "Web dashboard: %s", web.url)
+			if !opts.noBrowser {
+				openBrowser(web.url)
+			}
+		}
+	}
 
-	// Display user-friendly connection info
-	fileBrowserURL := fmt.Sprintf("https://%s-8080.proxy.runpod.net/FILE%%20TRANSFERS/", podID)
-	fmt.Println()
-	fmt.Println("╔════════════════════════════════════════════════════════════╗")
-	fmt.Println("║  YOUR SESSION IS READY                                     ║")
-	fmt.Println("╠════════════════════════════════════════════════════════════╣")
-	fmt.Println("║                                                            ║")
-	fmt.Println("║  Desktop (opens automatically in browser):                 ║")
-	fmt.Printf("║    %s%s%s\n", colorCyan, vncURL, colorReset)
-	fmt.Println("║                                                            ║")
-	fmt.Println("║  File Upload (drag & drop files):                          ║")
-	fmt.Printf("║    %s%s%s\n", colorCyan, fileBrowserURL, colorReset)
-	fmt.Printf("║    Login: %sadmin%s / %srunpod%s\n", colorGreen, colorReset, colorGreen, colorReset)
-	fmt.Println("║                                                            ║")
-	if tcpPorts != nil {
-		fmt.Printf("%s║  Advanced: ", colorDim)
-		if port, ok := tcpPorts[5901]; ok {
-			fmt.Printf("VNC %s:%d ", port.IP, port.PublicPort)
-		}
-		if port, ok := tcpPorts[22]; ok {
-			fmt.Printf("│ SSH -p %d", port.PublicPort)
-		}
-		fmt.Printf("%s\n", colorReset)
-	}
-	fmt.Println("╚════════════════════════════════════════════════════════════╝")
-
-	// Open noVNC first
-	fmt.Println()
-	fmt.Println("Opening desktop (noVNC)...")
-	if err := openBrowser(vncURL); err != nil {
-		fmt.Printf("Could not open browser. Open this URL: %s\n", vncURL)
+	// Optional Prometheus endpoint
+	if opts.metrics != "" {
+		if m, err := startMetrics(opts.metrics, profile.Name); err != nil {
+			logEvent(levelWarn, "Metrics unavailable: %v", err)
+		} else {
+			activeMetrics.Store(m)
+			subscribe(m.handle)
+			defer m.close()
+			logEvent(levelInfo, "Prometheus metrics: %s", m.url)
+		}
 	}
 
-	// Wait for File Browser and open it second (so it's the active tab)
-	fileBrowserCheckURL := fmt.Sprintf("https://%s-8080.proxy.runpod.net", podID)
-	waitForFileBrowser(fileBrowserCheckURL, fileBrowserURL)
+	// Switch to the full-screen dashboard if requested
+	var tui *tuiUI
+	if opts.tui && console != nil {
+		if tui, err = startTUI(); err != nil {
+			logEvent(levelWarn, "Dashboard unavailable (%v) - using console output", err)
+		} else {
+			console.setMuted(true)
+			closeDashboard = func() {
+				tui.close()
+				console.setMuted(false)
+			}
+		}
+	}
+	plain := console != nil && tui == nil
+	leaveDashboard := func() {
+		if tui != nil && closeDashboard != nil {
+			closeDashboard()
+			closeDashboard = nil
+		}
+	}
 
-	fmt.Println()
-	fmt.Printf("%s⚠  IMPORTANT: Closing this window terminates the pod!%s\n", colorYellow, colorReset)
-	fmt.Println()
+	// Start timing
+	launchStart = time.Now()
+	if guard.maxLifetime > 0 {
+		guard.deadline = launchStart.Add(guard.maxLifetime)
+	}
 
-	// Show initial balance
-	if info, err := getAccountInfo(apiKey); err == nil {
-		fmt.Printf("Balance: %s$%.2f%s │ Cost: %s$%.2f/hr%s │ Runtime: ~%.1f hrs\n",
-			colorGreen, info.Balance, colorReset, colorRed, info.CostPerHr, colorReset, info.Balance/info.CostPerHr)
+	if activeCase != "" {
+		logEvent(levelInfo, "Case %s", activeCase)
 	}
-	fmt.Println()
+	emit(Event{Type: evLaunching, Template: profile.TemplateID, Volume: profile.NetworkVolumeID, GPU: profile.GPUTypes[0]})
+
+	// Pods stuck in the GPU queue or the image pull are replaced until the
+	// launch deadline (see retry.go), and spot pods RunPod takes back as the
+	// profile's onInterrupt says (see spot.go)
+	plan := newLaunchPlan(profile)
+	limits := opts.launchLimits(launchStart)
+	podEnv := guard.env()
+	if activeCase != "" {
+		podEnv[caseEnv] = activeCase
+	}
+	for {
+		var (
+			s          *session
+			stop       chan struct{}
+			tcpPorts   map[int]PortInfo
+			podID      string
+			dataCenter string
+		)
+
+		// replace stops the attempt and terminates its pod, for another
+		// attempt to take over
+		replace := func() bool {
+			if web != nil {
+				web.setSession(nil)
+			}
+			close(stop)
+			if err := abandonPod(apiKey, podID); err != nil {
+				leaveDashboard()
+				reportTerminationFailure(apiKey, podID, err)
+				waitForKey("Press Enter to exit...")
+				return false
+			}
+			return true
+		}
 
-	// Start balance update goroutine
-	done := make(chan bool)
-	go func() {
-		ticker := time.NewTicker(5 * time.Minute)
-		defer ticker.Stop()
-		for {
-			select {
-			case <-ticker.C:
-				if info, err := getAccountInfo(apiKey); err == nil {
-					elapsed := time.Since(launchStart)
-					fmt.Printf("\rBalance: %s$%.2f%s │ Cost: %s$%.2f/hr%s │ Session: %s\n",
-						colorGreen, info.Balance, colorReset, colorRed, info.CostPerHr, colorReset,
-						formatDuration(elapsed))
-					fmt.Print("Press Enter to TERMINATE pod and exit...")
+		for attempt := 1; ; attempt++ {
+			pod, err := launchPod(apiKey, launchPodName(opts.caseID), plan.current(), podEnv)
+			if err != nil {
+				decision, retry := "", false
+				if attempt > 1 && !limits.expired() {
+					decision, retry = plan.next(err, "", "")
+				}
+				if !retry {
+					leaveDashboard()
+					logEvent(levelError, "launching pod: %v", err)
+					showLogHint()
+					waitForEnter()
+					return exitLaunchFailed
+				}
+				emit(Event{Type: evRetry, Message: fmt.Sprintf("Could not create the pod (%v). %s", err, decision), ElapsedSec: sessionElapsed()})
+				continue
+			}
+			podID, dataCenter = pod.ID, pod.Machine.DataCenterID
+			stop = startAttempt(apiKey, pod, opts)
+
+			// Wait for pod to be ready with progress display
+			s = newSession(apiKey, podID, pod.Machine.GpuDisplayName, guard)
+			if web != nil {
+				web.setSession(s)
+			}
+			_, tcpPorts, err = waitForPodReady(apiKey, podID, s.desktopURL, plan.current().Spot, limits.forPlan(plan))
+
+			var stuck *stuckError
+			if errors.As(err, &stuck) {
+				decision, retry := "", false
+				if !limits.expired() {
+					decision, retry = plan.next(stuck, pod.Machine.GpuDisplayName, pod.Machine.DataCenterID)
+				}
+				if retry {
+					emit(Event{Type: evRetry, Message: fmt.Sprintf("Pod %s %v. %s", podID, stuck, decision), ElapsedSec: sessionElapsed()})
+					if !replace() {
+						return exitError
+					}
+					continue
+				}
+				err = fmt.Errorf("%w - no other GPU type or data center left to try", stuck)
+			}
+
+			var interrupted *interruptedError
+			if errors.As(err, &interrupted) {
+				decision, retry := "", false
+				if !limits.expired() {
+					decision, retry = plan.interrupted(pod.Machine.GpuDisplayName, pod.Machine.DataCenterID)
+				}
+				if retry {
+					emit(Event{Type: evRetry, Message: fmt.Sprintf("%s. %s", capitalize(interrupted.Error()), decision), ElapsedSec: sessionElapsed()})
+					if !replace() {
+						return exitError
+					}
+					continue
+				}
+				// The pod will never come up, whatever --on-not-ready says
+				leaveDashboard()
+				logEvent(levelError, "%v", err)
+				close(stop)
+				if code := endSession(apiKey, podID); code != exitOK {
+					return code
+				}
+				return exitInterrupted
+			}
+
+			if err != nil {
+				if opts.onNotReady == notReadyTerminate {
+					leaveDashboard()
+					logEvent(levelError, "%v", err)
+					showLogHint()
+					close(stop)
+					if code := endSession(apiKey, podID); code != exitOK {
+						return code
+					}
+					return exitNotReady
+				}
+				logEvent(levelWarn, "%v", err)
+				if !opts.noBrowser {
+					logEvent(levelInfo, "Opening browser anyway...")
+				}
+			}
+			break
+		}
+		s.tcpPorts = tcpPorts
+
+		emit(Event{
+			Type:       evReady,
+			ElapsedSec: sessionElapsed(),
+			DesktopURL: s.desktopURL,
+			FilesURL:   s.filesURL,
+			Ports:      tcpPorts,
+		})
+
+		// Open noVNC first
+		if plain {
+			fmt.Println()
+		}
+		if !opts.noBrowser {
+			logEvent(levelInfo, "Opening desktop (noVNC)...")
+			s.openDesktop()
+		}
+
+		// Wait for File Browser and open it second (so it's the active tab)
+		fileBrowserCheckURL := podURL(podID, fileBrowserPort)
+		fileBrowserUp := waitForFileBrowser(fileBrowserCheckURL)
+		if fileBrowserUp && !opts.noBrowser {
+			logEvent(levelInfo, "Opening File Browser (for uploads)...")
+			s.openFiles()
+		}
+		if fileBrowserUp && profile.NetworkVolumeID != "" {
+			warnLowSpace(s.fb)
+		}
+
+		if opts.detach {
+			leaveDashboard()
+			close(stop)
+			if d := guard.currentDeadline(); !d.IsZero() {
+				emit(Event{Type: evBudget, Deadline: &d})
+			}
+			logEvent(levelInfo, "Leaving pod %s running. Terminate it from the RunPod console when done.", podID)
+			activePodID = ""
+			return exitOK
+		}
+
+		// Keep the pod-side safety net fed via the File Browser API
+		startPodHeartbeat(guard, s.fb, stop)
+
+		if plain {
+			fmt.Println()
+			fmt.Printf("%s⚠  IMPORTANT: Closing this window terminates the pod!%s\n", colorYellow, colorReset)
+		}
+		if d := guard.currentDeadline(); !d.IsZero() {
+			emit(Event{Type: evBudget, Deadline: &d})
+		}
+		if plain {
+			fmt.Println()
+		}
+
+		// Show balance now and every few minutes, and enforce the session budget
+		s.trackBalance(stop)
+		if plain {
+			fmt.Println()
+		}
+		s.watchBudget(stop)
+		s.watchIdle(time.Duration(appSettings.IdleMinutes)*time.Minute, stop)
+		if plan.current().Spot {
+			s.watchInterruption(stop)
+		}
+
+		var keep bool
+		switch {
+		case tui != nil:
+			keep = tui.run(s)
+		case console != nil && interactive:
+			keep = console.run(s)
+		default:
+			// Runs until a signal arrives or the budget runs out
+			keep = runHeadless(s, opts.json && !opts.nonInteractive)
+		}
+		close(stop)
+
+		// RunPod took the spot pod back: relaunch and wait for the desktop
+		// again, or tell the user the session is over
+		if s.wasInterrupted() {
+			decision, retry := plan.interrupted(s.gpuName, dataCenter)
+			if retry {
+				emit(Event{Type: evRetry, Message: fmt.Sprintf("Pod %s was interrupted by RunPod. %s - the desktop opens again when it is ready", podID, decision), ElapsedSec: sessionElapsed()})
+				if err := abandonPod(apiKey, podID); err != nil {
+					leaveDashboard()
+					reportTerminationFailure(apiKey, podID, err)
+					waitForKey("Press Enter to exit...")
+					return exitError
+				}
+				if web != nil {
+					web.setSession(nil)
 				}
-			case <-done:
-				return
+				limits = opts.launchLimits(time.Now())
+				continue
+			}
+			leaveDashboard()
+			logEvent(levelError, "The session ended because RunPod took the spot pod back. Use --on-interrupt (or \"onInterrupt\" in the profile) to relaunch automatically.")
+			if code := endSession(apiKey, podID); code != exitOK {
+				return code
 			}
+			return exitInterrupted
 		}
-	}()
+		leaveDashboard()
+
+		if keep {
+			logEvent(levelInfo, "Pod %s left stopped. Terminate it from the RunPod console when done.", podID)
+			if activeHeartbeat != "" {
+				finishHeartbeat(activeHeartbeat)
+			}
+			activePodID = ""
+			return exitOK
+		}
+
+		// Terminate pod on exit
+		return endSession(apiKey, podID)
+	}
+}
 
-	waitForEnter()
-	done <- true
+// startAttempt records a freshly created pod and starts its watchdog. The
+// returned channel stops the heartbeats.
+func startAttempt(apiKey string, pod *PodResponse, opts launchOptions) chan struct{} {
+	// Store for cleanup on exit
+	activeAPIKey = apiKey
+	activePodID = pod.ID
+	if err := recordPod(PodRecord{ID: pod.ID, CreatedAt: time.Now(), Status: podStatusActive, Account: keyAccount(apiKey)}); err != nil {
+		logEvent(levelWarn, "Could not record pod in launcher state: %v", err)
+	}
+	emit(Event{Type: evCreated, PodID: pod.ID, GPU: pod.Machine.GpuDisplayName, DataCenter: pod.Machine.DataCenterID, CostPerHr: pod.CostPerHr})
+
+	// Heartbeats let the watchdog tell a crashed launcher from a running one
+	stop := make(chan struct{})
+	if opts.watchdog {
+		if hbPath, err := heartbeatPath(pod.ID); err != nil {
+			logEvent(levelWarn, "Watchdog not started: %v", err)
+		} else {
+			startHeartbeat(hbPath, stop)
+			if err := spawnWatchdog(apiKey, pod.ID, hbPath, opts.deadman); err != nil {
+				logEvent(levelWarn, "Watchdog not started: %v", err)
+			} else {
+				activeHeartbeat = hbPath
+				logEvent(levelOK, "Watchdog started (terminates pod %s after launcher exits)", formatDuration(opts.deadman))
+			}
+		}
+	}
+	return stop
+}
 
-	// Terminate pod on exit
+// abandonPod deletes a pod that is being replaced by another attempt
+func abandonPod(apiKey, podID string) error {
+	if err := deletePod(apiKey, podID); err != nil {
+		// Leave the heartbeat stale so the watchdog retries the termination
+		return err
+	}
+	if activeHeartbeat != "" {
+		finishHeartbeat(activeHeartbeat)
+		activeHeartbeat = ""
+	}
+	activePodID = ""
+	return nil
+}
+
+// showLogHint points at the session log after a failure
+func showLogHint() {
+	if sessionLogPath != "" {
+		logEvent(levelInfo, "Details in %s (run 'slicer-launcher support-bundle' to share them)", sessionLogPath)
+	}
+}
+
+// endSession terminates the session's pod and returns the exit code
+func endSession(apiKey, podID string) int {
 	if err := terminatePod(apiKey, podID); err != nil {
-		fmt.Printf("Warning: %v\n", err)
+		// Leave the heartbeat stale so the watchdog retries the termination
+		reportTerminationFailure(apiKey, podID, err)
+		waitForKey("Press Enter to exit...")
+		return exitError
 	}
+	if activeHeartbeat != "" {
+		finishHeartbeat(activeHeartbeat)
+	}
+	return exitOK
 }
 
 // formatDuration formats a duration in a human-friendly way
@@ -241,50 +847,45 @@ func getConfigPath() (string, error) {
 	return filepath.Join(home, configFile), nil
 }
 
-func loadSavedKey() (string, error) {
-	configPath, err := getConfigPath()
-	if err != nil {
-		return "", err
-	}
-
-	data, err := os.ReadFile(configPath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return "", nil // No saved key
-		}
-		return "", fmt.Errorf("could not read config file: %w", err)
-	}
-
-	return strings.TrimSpace(string(data)), nil
-}
-
-func saveKey(apiKey string) error {
-	configPath, err := getConfigPath()
-	if err != nil {
-		return err
-	}
-
-	return os.WriteFile(configPath, []byte(apiKey), 0600)
-}
-
-func getAPIKey() (string, error) {
-	// Try to load saved key
-	savedKey, err := loadSavedKey()
+// getAPIKey returns the account and key to launch with, asking for a key
+// when none is saved for the account ("" = the default account)
+func getAPIKey(account string) (string, string, error) {
+	creds, err := loadCredentials()
 	if err != nil {
-		fmt.Printf("Warning: Could not check for saved key: %v\n", err)
+		fmt.Printf("Warning: Could not check for saved keys: %v\n", err)
+		creds = &Credentials{Accounts: make(map[string]string)}
 	}
+	creds.addSecrets()
 
+	name, savedKey := creds.lookup(account)
+	reader := bufio.NewReader(os.Stdin)
 	if savedKey != "" {
-		fmt.Println("Using saved API key.")
-		fmt.Print("Press Enter to continue or type 'new' for a new key: ")
+		fmt.Printf("Using saved API key (account %s).\n", name)
+		if account != "" {
+			fmt.Print("Press Enter to continue or type 'new' to replace it: ")
+		} else {
+			fmt.Print("Press Enter to continue or type 'new' to add a key for another account: ")
+		}
 
-		reader := bufio.NewReader(os.Stdin)
 		input, _ := reader.ReadString('\n')
 		input = strings.TrimSpace(strings.ToLower(input))
 
 		if input != "new" {
-			return savedKey, nil
+			// A saved key that stopped working is replaced below
+			_, err := checkAPIKey(savedKey)
+			if !keyProblem(err) {
+				return name, savedKey, nil
+			}
+			fmt.Printf("%sThe saved key of account %s no longer works: %v%s\n", colorRed, name, err, colorReset)
+		} else {
+			// A new key for the account asked for replaces its saved one
+			name = account
 		}
+	} else if account != "" {
+		fmt.Printf("No API key saved for account %q.\n", account)
+	} else if len(creds.Accounts) > 1 {
+		return "", "", fmt.Errorf("several accounts are saved (%s) but none is the default - pick one with --account",
+			strings.Join(creds.names(), ", "))
 	}
 
 	// Prompt for new key
@@ -299,141 +900,204 @@ func getAPIKey() (string, error) {
 	fmt.Println()
 	fmt.Print("Paste your API key: ")
 
-	reader := bufio.NewReader(os.Stdin)
 	apiKey, err := reader.ReadString('\n')
 	if err != nil {
-		return "", fmt.Errorf("could not read input: %w", err)
+		return "", "", fmt.Errorf("could not read input: %w", err)
 	}
 	apiKey = strings.TrimSpace(apiKey)
 
 	if apiKey == "" {
-		return "", fmt.Errorf("API key cannot be empty")
+		return "", "", fmt.Errorf("API key cannot be empty")
+	}
+	addSecret(apiKey)
+
+	// Keys that can't launch are neither saved nor used
+	if _, err := checkNewAPIKey(apiKey); keyProblem(err) {
+		return "", "", err
 	}
 
-	// Offer to save
+	// Offer to save, under a name so other accounts' keys are kept
 	fmt.Print("Save this key for future use? (y/n): ")
 	saveChoice, _ := reader.ReadString('\n')
 	saveChoice = strings.TrimSpace(strings.ToLower(saveChoice))
 
-	if saveChoice == "y" || saveChoice == "yes" {
-		if err := saveKey(apiKey); err != nil {
-			fmt.Printf("Warning: Could not save key: %v\n", err)
-		} else {
-			configPath, _ := getConfigPath()
-			fmt.Printf("Key saved to: %s\n", configPath)
+	if saveChoice != "y" && saveChoice != "yes" {
+		return name, apiKey, nil
+	}
+	if name == "" {
+		name = defaultAccountName
+		if len(creds.Accounts) > 0 {
+			name = ""
+		}
+		for {
+			if name != "" {
+				fmt.Printf("Account name [%s]: ", name)
+			} else {
+				fmt.Print("Account name (e.g. grants, teaching): ")
+			}
+			input, _ := reader.ReadString('\n')
+			if input = strings.TrimSpace(input); input != "" {
+				name = input
+			}
+			if err := validAccountName(name); err != nil {
+				fmt.Printf("%v\n", err)
+				name = ""
+				continue
+			}
+			break
 		}
 	}
+	creds.set(name, apiKey)
+	if err := creds.save(); err != nil {
+		fmt.Printf("Warning: Could not save key: %v\n", err)
+	} else {
+		path, _ := credentialsPath()
+		fmt.Printf("Key saved as account %s to: %s\n", name, path)
+	}
+
+	return name, apiKey, nil
+}
+
+// getAPIKeyNoPrompt returns the key from the environment, falling back to
+// the saved key, for runs that must not prompt. A named account always
+// uses its saved key.
+func getAPIKeyNoPrompt(account string) (string, error) {
+	if key := strings.TrimSpace(os.Getenv(apiKeyEnv)); key != "" && account == "" {
+		return key, nil
+	}
+	creds, err := loadCredentials()
+	if err != nil {
+		return "", err
+	}
+	creds.addSecrets()
+	name, savedKey := creds.lookup(account)
+	switch {
+	case savedKey != "":
+		return savedKey, nil
+	case account != "":
+		return "", fmt.Errorf("no API key saved for account %q", account)
+	case len(creds.Accounts) > 1:
+		return "", fmt.Errorf("%s is not set and no default account is saved - pick one with --account", apiKeyEnv)
+	case name == "":
+		return "", fmt.Errorf("%s is not set and no key is saved", apiKeyEnv)
+	}
+	return "", fmt.Errorf("no API key saved for account %q", name)
+}
+
+// commandSetup loads the settings and the API key for subcommands, which
+// never prompt
+func commandSetup() (string, error) {
+	var err error
+	if appSettings, err = loadSettings(); err != nil {
+		return "", err
+	}
+	appSettings.addSettingsSecrets()
 
+	apiKey, err := getAPIKeyNoPrompt(selectedAccount)
+	if err != nil {
+		return "", fmt.Errorf("getting API key: %w", err)
+	}
+	addSecret(apiKey)
 	return apiKey, nil
 }
 
-func launchPod(apiKey string) (string, string, error) {
+func launchPod(apiKey, name string, profile Profile, env map[string]string) (*PodResponse, error) {
+	if profile.Spot {
+		return launchSpotPod(apiKey, name, profile, env)
+	}
+
 	// Build the request
 	reqBody := PodRequest{
-		Name:            fmt.Sprintf("slicer-%d", time.Now().Unix()),
-		TemplateID:      templateID,
-		NetworkVolumeID: networkVolumeID,
-		GPUTypeIDs:      gpuTypes,
+		Name:            name,
+		TemplateID:      profile.TemplateID,
+		NetworkVolumeID: profile.NetworkVolumeID,
+		GPUTypeIDs:      profile.GPUTypes,
+		DataCenterIDs:   profile.DataCenters,
 		GPUCount:        1,
+		Env:             env,
 	}
 
 	jsonBody, err := json.Marshal(reqBody)
 	if err != nil {
-		return "", "", fmt.Errorf("could not create request body: %w", err)
+		return nil, fmt.Errorf("could not create request body: %w", err)
 	}
 
 	// Create HTTP request
 	req, err := http.NewRequest("POST", runpodAPIURL, bytes.NewBuffer(jsonBody))
 	if err != nil {
-		return "", "", fmt.Errorf("could not create request: %w", err)
+		return nil, fmt.Errorf("could not create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
 
 	// Send request
-	client := &http.Client{Timeout: 30 * time.Second}
+	client := newHTTPClient(30 * time.Second)
 	resp, err := client.Do(req)
 	if err != nil {
-		return "", "", fmt.Errorf("API request failed: %w", err)
+		return nil, fmt.Errorf("API request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// Read response
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", "", fmt.Errorf("could not read response: %w", err)
+		return nil, fmt.Errorf("could not read response: %w", err)
 	}
 
 	// Check for errors
+	// The key was checked at startup, so a refusal means it is read-only
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return nil, errKeyReadOnly
+	}
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
 		var errResp ErrorResponse
 		if json.Unmarshal(body, &errResp) == nil && errResp.Error != "" {
-			return "", "", fmt.Errorf("API error (%d): %s", resp.StatusCode, errResp.Error)
+			return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, errResp.Error)
 		}
-		return "", "", fmt.Errorf("API error (%d): %s", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, string(body))
 	}
 
 	// Parse successful response
 	var podResp PodResponse
 	if err := json.Unmarshal(body, &podResp); err != nil {
-		return "", "", fmt.Errorf("could not parse response: %w (body: %s)", err, string(body))
+		return nil, fmt.Errorf("could not parse response: %w (body: %s)", err, string(body))
 	}
 
 	if podResp.ID == "" {
-		return "", "", fmt.Errorf("no pod ID in response: %s", string(body))
+		return nil, fmt.Errorf("no pod ID in response: %s", string(body))
 	}
 
-	return podResp.ID, podResp.Machine.GpuDisplayName, nil
+	return &podResp, nil
 }
 
 // PortInfo holds TCP port mapping info
 type PortInfo struct {
-	IP         string
-	PublicPort int
+	IP         string `json:"ip"`
+	PublicPort int    `json:"publicPort"`
 }
 
-func waitForPodReady(apiKey, podID, vncURL string) (string, map[int]PortInfo, error) {
-	client := &http.Client{Timeout: 10 * time.Second}
+// waitForPodReady polls the pod until the desktop answers. It gives up with
+// a *stuckError when the pod sits in a phase longer than limits allow, and
+// with an *interruptedError when RunPod takes back a spot pod.
+func waitForPodReady(apiKey, podID, vncURL string, spot bool, limits launchLimits) (string, map[int]PortInfo, error) {
+	client := newHTTPClient(10 * time.Second)
 
 	var publicIP string
 	var tcpPorts map[int]PortInfo
 
-	// Clear line helper - clears entire line
-	clearLine := "\r\033[K"
-
-	spinner := []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
-	spinIdx := 0
 	lastPhase := ""
-
-	// Tips to show while waiting
-	tips := []string{
-		"Your files persist on the network volume at /workspace",
-		"Use File Browser to drag & drop files directly to the pod",
-		"TurboVNC client gives better performance than browser",
-		"The nnInteractive server starts automatically with the desktop",
-		"Click '3D Slicer' on the desktop to start segmenting",
-		"GPU-accelerated apps: 3D Slicer, Blender, Fiji",
-		"SSH access: root / runpod (see connection info)",
-		"Claude Code CLI is pre-installed - just type 'claude'",
-		"Closing this window auto-terminates the pod",
-		"Balance updates every 5 minutes while running",
-		"T2 DICOM folders auto-load into Slicer when uploaded",
-		"lazygit is available via the GitHub desktop shortcut",
-	}
-	tipIdx := 0
-	lastTipTime := time.Now()
+	phaseStart := time.Now()
 
 	// Phase 1: Wait for pod to have public ports
-	for i := 0; i < 180; i++ { // Max 6 minutes
+	for !limits.expired() {
 		query := fmt.Sprintf(`{"query": "query { pod(input: {podId: \"%s\"}) { id desiredStatus runtime { ports { ip isIpPublic privatePort publicPort type } gpus { id } } } }"}`, podID)
 		req, _ := http.NewRequest("POST", runpodGraphQLURL+"?api_key="+apiKey, strings.NewReader(query))
 		req.Header.Set("Content-Type", "application/json")
 
 		resp, err := client.Do(req)
 		if err != nil {
-			spinIdx = (spinIdx + 1) % len(spinner)
-			fmt.Printf("%s  %s Connecting...    ", clearLine, spinner[spinIdx])
+			emit(Event{Type: evProgress, PodID: podID, Phase: "Connecting...", ElapsedSec: sessionElapsed()})
 			time.Sleep(2 * time.Second)
 			continue
 		}
@@ -496,113 +1160,82 @@ func waitForPodReady(apiKey, podID, vncURL string) (string, map[int]PortInfo, er
 			}
 		}
 
-		elapsed := time.Since(launchStart)
-		spinIdx = (spinIdx + 1) % len(spinner)
+		// A spot pod RunPod takes back while starting never comes up; an
+		// on-demand pod that exits has a container that failed to start
+		if result.Data.Pod.DesiredStatus == "EXITED" {
+			if !spot {
+				return "", nil, fmt.Errorf("pod %s exited while starting (%q)", podID, phaseName)
+			}
+			err := &interruptedError{podID: podID, phase: phaseName}
+			podInterrupted(err)
+			return "", nil, err
+		}
 
 		// Track phase changes
 		if phaseName != lastPhase {
 			if lastPhase != "" {
-				// Clear both lines (status + tip) and print completed phase
-				fmt.Print("\033[1B")  // Move down to tip line
-				fmt.Printf("%s", clearLine)  // Clear tip line
-				fmt.Print("\033[1A")  // Move back up
-				fmt.Printf("%s  %s✓%s %s\n", clearLine, colorGreen, colorReset, lastPhase)
+				emit(Event{Type: evPhaseDone, PodID: podID, Phase: lastPhase, ElapsedSec: sessionElapsed()})
 			}
 			lastPhase = phaseName
+			phaseStart = time.Now()
 		}
-
-		// Show current phase with spinner and elapsed time
-		statusLine := fmt.Sprintf("  %s %s", spinner[spinIdx], phaseName)
-		if phaseDetail != "" {
-			statusLine += fmt.Sprintf(" (%s)", phaseDetail)
-		}
-		statusLine += fmt.Sprintf(" - %s", formatDuration(elapsed))
-
-		// Rotate tips every 5 seconds
-		if time.Since(lastTipTime) > 5*time.Second {
-			tipIdx = (tipIdx + 1) % len(tips)
-			lastTipTime = time.Now()
+		if err := limits.check(phaseName, time.Since(phaseStart)); err != nil {
+			return "", nil, err
 		}
 
-		// Show status with tip on second line
-		fmt.Printf("%s%s\n", clearLine, statusLine)
-		fmt.Printf("%s    %s💡 %s%s", clearLine, colorDim, tips[tipIdx], colorReset)
-		// Move cursor back up one line for next update
-		fmt.Print("\033[1A")
-
 		if phaseName == "Running" && publicIP != "" {
-			// Clear both lines and print completion
-			fmt.Print("\033[1B")  // Move down to tip line
-			fmt.Printf("%s", clearLine)  // Clear tip line
-			fmt.Print("\033[1A")  // Move back up
-			fmt.Printf("%s  %s✓%s %s\n", clearLine, colorGreen, colorReset, phaseName)
+			emit(Event{Type: evPhaseDone, PodID: podID, Phase: phaseName, ElapsedSec: sessionElapsed()})
 			break
 		}
 
+		// Show current phase with elapsed time
+		emit(Event{Type: evProgress, PodID: podID, Phase: phaseName, Detail: phaseDetail, ElapsedSec: sessionElapsed()})
+
 		time.Sleep(2 * time.Second)
 	}
+	if publicIP == "" {
+		if lastPhase == "" {
+			lastPhase = "Connecting"
+		}
+		return "", nil, fmt.Errorf("launch deadline reached while %q", lastPhase)
+	}
 
 	// Phase 2: Wait for VNC port to be accessible
-	spinIdx = 0
-	tipIdx = 0
-	lastTipTime = time.Now()
-	for i := 0; i < 60; i++ { // Max 2 minutes
+	for i := 0; i < 60 && !limits.expired(); i++ { // Max 2 minutes
 		resp, err := client.Get(vncURL)
 		if err == nil {
 			resp.Body.Close()
 			if resp.StatusCode == 200 || resp.StatusCode == 302 || resp.StatusCode == 401 {
-				// Clear both lines and print completion
-				fmt.Print("\033[1B")  // Move down to tip line
-				fmt.Printf("%s", clearLine)  // Clear tip line
-				fmt.Print("\033[1A")  // Move back up
-				fmt.Printf("%s  %s✓%s Desktop ready\n", clearLine, colorGreen, colorReset)
+				emit(Event{Type: evPhaseDone, PodID: podID, Phase: phaseDesktopReady, ElapsedSec: sessionElapsed()})
 				return publicIP, tcpPorts, nil
 			}
 		}
-		spinIdx = (spinIdx + 1) % len(spinner)
-		elapsed := time.Since(launchStart)
-
-		// Rotate tips every 5 seconds
-		if time.Since(lastTipTime) > 5*time.Second {
-			tipIdx = (tipIdx + 1) % len(tips)
-			lastTipTime = time.Now()
-		}
-
-		// Show status with tip
-		fmt.Printf("%s  %s Waiting for desktop - %s\n", clearLine, spinner[spinIdx], formatDuration(elapsed))
-		fmt.Printf("%s    %s💡 %s%s", clearLine, colorDim, tips[tipIdx], colorReset)
-		fmt.Print("\033[1A")  // Move cursor back up
 
+		emit(Event{Type: evProgress, PodID: podID, Phase: "Waiting for desktop", ElapsedSec: sessionElapsed()})
 		time.Sleep(2 * time.Second)
 	}
 
 	return publicIP, tcpPorts, fmt.Errorf("timeout waiting for VNC port")
 }
 
-func waitForFileBrowser(checkURL, openURL string) {
-	client := &http.Client{Timeout: 5 * time.Second}
-	spinner := []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
-	spinIdx := 0
-	clearLine := "\r\033[K"
-
-	fmt.Printf("  %s Waiting for File Browser...", spinner[0])
+// waitForFileBrowser polls until File Browser answers and reports whether it did
+func waitForFileBrowser(checkURL string) bool {
+	client := newHTTPClient(5 * time.Second)
 
 	for i := 0; i < 60; i++ { // Max 3 minutes
 		resp, err := client.Get(checkURL)
 		if err == nil {
 			resp.Body.Close()
 			if resp.StatusCode == 200 || resp.StatusCode == 401 || resp.StatusCode == 302 {
-				fmt.Printf("%s  %s✓%s File Browser ready\n", clearLine, colorGreen, colorReset)
-				fmt.Println("Opening File Browser (for uploads)...")
-				openBrowser(openURL)
-				return
+				emit(Event{Type: evFileBrowserReady, ElapsedSec: sessionElapsed()})
+				return true
 			}
 		}
-		spinIdx = (spinIdx + 1) % len(spinner)
-		fmt.Printf("%s  %s Waiting for File Browser...", clearLine, spinner[spinIdx])
+		emit(Event{Type: evProgress, Phase: "Waiting for File Browser", ElapsedSec: sessionElapsed()})
 		time.Sleep(3 * time.Second)
 	}
-	fmt.Printf("%s  %s⚠%s File Browser not detected (may need manual start)\n", clearLine, colorYellow, colorReset)
+	emit(Event{Type: evFileBrowserMissing, ElapsedSec: sessionElapsed()})
+	return false
 }
 
 func openBrowser(url string) error {
@@ -620,18 +1253,101 @@ func openBrowser(url string) error {
 	return cmd.Start()
 }
 
+// copyToClipboard puts text on the system clipboard
+func copyToClipboard(text string) error {
+	var cmd *exec.Cmd
+
+	switch runtime.GOOS {
+	case "windows":
+		cmd = exec.Command("clip")
+	case "darwin":
+		cmd = exec.Command("pbcopy")
+	default: // Linux: Wayland first, then X11
+		if _, err := exec.LookPath("wl-copy"); err == nil {
+			cmd = exec.Command("wl-copy")
+		} else {
+			cmd = exec.Command("xclip", "-selection", "clipboard")
+		}
+	}
+
+	cmd.Stdin = strings.NewReader(text)
+	return cmd.Run()
+}
+
 func waitForEnter() {
-	fmt.Print("Press Enter to TERMINATE pod and exit...")
+	waitForKey("Press Enter to TERMINATE pod and exit...")
+}
+
+func waitForKey(prompt string) {
+	if !interactive {
+		return
+	}
+	fmt.Print(prompt)
 	bufio.NewReader(os.Stdin).ReadBytes('\n')
 }
 
+// confirm asks a yes/no question on the terminal
+func confirm(prompt string) bool {
+	fmt.Print(prompt)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.TrimSpace(strings.ToLower(answer))
+	return answer == "y" || answer == "yes"
+}
+
 func terminatePod(apiKey, podID string) error {
 	if podID == "" || apiKey == "" {
 		return nil
 	}
 
-	fmt.Printf("\nTerminating pod %s...\n", podID)
+	emit(Event{Type: evTerminating, PodID: podID})
+	if err := deletePod(apiKey, podID); err != nil {
+		return err
+	}
+	emit(Event{Type: evTerminated, PodID: podID, ElapsedSec: sessionElapsed()})
+	return nil
+}
+
+// deletePod terminates a pod, waits until RunPod confirms it and removes it
+// from the launcher state
+func deletePod(apiKey, podID string) error {
+	if err := sendTerminate(apiKey, podID); err != nil {
+		// The pod may still have gone away, so verify before giving up
+		logEvent(levelWarn, "%v", err)
+	}
+
+	if err := verifyTermination(apiKey, podID); err != nil {
+		return err
+	}
+
+	if err := forgetPod(podID); err != nil {
+		logEvent(levelWarn, "Could not update launcher state: %v", err)
+	}
+	return nil
+}
+
+// stopPod stops a pod; its disk is kept and only storage is billed
+func stopPod(apiKey, podID string) error {
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s/%s/stop", runpodAPIURL, podID), nil)
+	if err != nil {
+		return fmt.Errorf("could not create request: %w", err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
+
+	client := newHTTPClient(30 * time.Second)
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to stop pod (%d): %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
 
+func sendTerminate(apiKey, podID string) error {
 	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/%s", runpodAPIURL, podID), nil)
 	if err != nil {
 		return fmt.Errorf("could not create request: %w", err)
@@ -639,7 +1355,7 @@ func terminatePod(apiKey, podID string) error {
 
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
 
-	client := &http.Client{Timeout: 30 * time.Second}
+	client := newHTTPClient(30 * time.Second)
 	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("API request failed: %w", err)
@@ -647,7 +1363,6 @@ func terminatePod(apiKey, podID string) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode == 200 || resp.StatusCode == 204 {
-		fmt.Println("✓ Pod terminated successfully!")
 		return nil
 	}
 
@@ -655,17 +1370,105 @@ func terminatePod(apiKey, podID string) error {
 	return fmt.Errorf("failed to terminate pod (%d): %s", resp.StatusCode, string(body))
 }
 
+const (
+	terminateVerifyAttempts = 10
+	terminateVerifyInterval = 3 * time.Second
+)
+
+// verifyTermination polls the pod until it is gone or TERMINATED.
+// The delete request is re-sent every few attempts in case it was lost.
+func verifyTermination(apiKey, podID string) error {
+	var lastStatus string
+	for attempt := 1; attempt <= terminateVerifyAttempts; attempt++ {
+		status, err := getPodStatus(apiKey, podID)
+		if err == nil {
+			if status == "" || status == "TERMINATED" {
+				return nil
+			}
+			lastStatus = status
+		}
+		logEvent(levelInfo, "  Waiting for termination to be confirmed (%d/%d)...", attempt, terminateVerifyAttempts)
+
+		if attempt%3 == 0 {
+			sendTerminate(apiKey, podID)
+		}
+		time.Sleep(terminateVerifyInterval)
+	}
+
+	if lastStatus == "" {
+		return fmt.Errorf("could not confirm pod %s was terminated", podID)
+	}
+	return fmt.Errorf("pod %s still exists after %d checks (status: %s)", podID, terminateVerifyAttempts, lastStatus)
+}
+
+// getPodStatus returns the pod's desiredStatus, or "" if the pod no longer exists
+func getPodStatus(apiKey, podID string) (string, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/%s", runpodAPIURL, podID), nil)
+	if err != nil {
+		return "", fmt.Errorf("could not create request: %w", err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
+
+	client := newHTTPClient(10 * time.Second)
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", nil
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("API error (%d): %s", resp.StatusCode, string(body))
+	}
+
+	var pod PodResponse
+	if err := json.Unmarshal(body, &pod); err != nil {
+		return "", fmt.Errorf("could not parse response: %w", err)
+	}
+	if pod.ID == "" {
+		return "", nil
+	}
+	return pod.DesiredStatus, nil
+}
+
+// reportTerminationFailure makes an unconfirmed termination impossible to miss
+// and records the pod so the next run retries the cleanup
+func reportTerminationFailure(apiKey, podID string, err error) {
+	emit(Event{Type: evTerminateFailed, PodID: podID, Message: err.Error()})
+
+	if err := recordUnconfirmed(podID, keyAccount(apiKey)); err != nil {
+		logEvent(levelWarn, "Could not update launcher state: %v", err)
+	}
+}
+
 func setupSignalHandler() {
 	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	// SIGHUP is what macOS/Linux terminals send when the window is closed
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
 
 	go func() {
 		<-c
-		fmt.Println("\n\nReceived interrupt signal...")
+		if closeDashboard != nil {
+			closeDashboard()
+		}
+		if interactive {
+			fmt.Println()
+		}
+		logEvent(levelInfo, "Received interrupt signal...")
 		if activePodID != "" {
-			terminatePod(activeAPIKey, activePodID)
+			if err := terminatePod(activeAPIKey, activePodID); err != nil {
+				reportTerminationFailure(activeAPIKey, activePodID, err)
+				exit(exitError)
+			}
+			if activeHeartbeat != "" {
+				finishHeartbeat(activeHeartbeat)
+			}
 		}
-		os.Exit(0)
+		exit(exitOK)
 	}()
 }
 
@@ -684,7 +1487,7 @@ func getAccountInfo(apiKey string) (*AccountInfo, error) {
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{Timeout: 10 * time.Second}
+	client := newHTTPClient(10 * time.Second)
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
@@ -692,19 +1495,32 @@ func getAccountInfo(apiKey string) (*AccountInfo, error) {
 	defer resp.Body.Close()
 
 	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return nil, errKeyInvalid
+	}
 
 	var result struct {
 		Data struct {
-			Myself struct {
+			Myself *struct {
 				CurrentSpendPerHr float64 `json:"currentSpendPerHr"`
 				ClientBalance     float64 `json:"clientBalance"`
 			} `json:"myself"`
 		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
 	}
 
 	if err := json.Unmarshal(body, &result); err != nil {
 		return nil, err
 	}
+	// A zero balance from a failed query would look like an empty account
+	if len(result.Errors) > 0 {
+		return nil, fmt.Errorf("RunPod: %s", result.Errors[0].Message)
+	}
+	if result.Data.Myself == nil {
+		return nil, fmt.Errorf("RunPod returned no account")
+	}
 
 	return &AccountInfo{
 		Balance:   result.Data.Myself.ClientBalance,
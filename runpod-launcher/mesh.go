@@ -0,0 +1,557 @@
+// Copyright (c) 2025-2026 Mik Gangal
+// Licensed under CC BY-NC-SA 4.0 - https://creativecommons.org/licenses/by-nc-sa/4.0/
+
+package main
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Mesh checks for downloaded exports: STL (binary and ASCII) and OBJ files
+// are parsed and checked for what makes a print fail - holes, edges shared
+// by more than two triangles, flipped or inverted normals - plus the stats
+// needed to plan the print. Slicer exports in millimeters.
+
+// degenerateArea is the area (mm²) below which a triangle counts as degenerate
+const degenerateArea = 1e-10
+
+// meshReport is the result of checking one mesh
+type meshReport struct {
+	Name        string     `json:"name"`
+	Triangles   int        `json:"triangles"`
+	Min         [3]float64 `json:"min"`
+	Max         [3]float64 `json:"max"`
+	Volume      float64    `json:"volumeMm3"`
+	Area        float64    `json:"areaMm2"`
+	OpenEdges   int        `json:"openEdges"`
+	NonManifold int        `json:"nonManifoldEdges"`
+	Flipped     int        `json:"flippedEdges"`
+	Degenerate  int        `json:"degenerate"`
+	Shells      int        `json:"shells"`
+}
+
+func (r *meshReport) watertight() bool {
+	return r.Triangles > 0 && r.OpenEdges == 0 && r.NonManifold == 0
+}
+
+// repairs lists what has to be fixed before printing
+func (r *meshReport) repairs() []string {
+	var issues []string
+	if r.Triangles == 0 {
+		return []string{"no triangles"}
+	}
+	if r.OpenEdges > 0 {
+		issues = append(issues, fmt.Sprintf("%d open edges", r.OpenEdges))
+	}
+	if r.NonManifold > 0 {
+		issues = append(issues, fmt.Sprintf("%d non-manifold edges", r.NonManifold))
+	}
+	if r.Flipped > 0 {
+		issues = append(issues, fmt.Sprintf("%d edges with flipped normals", r.Flipped))
+	}
+	if r.watertight() && r.Flipped == 0 && r.Volume < 0 {
+		issues = append(issues, "normals point inwards")
+	}
+	return issues
+}
+
+// warnings lists what prints but may not be intended
+func (r *meshReport) warnings() []string {
+	var issues []string
+	if r.Degenerate > 0 {
+		issues = append(issues, fmt.Sprintf("%d degenerate triangles", r.Degenerate))
+	}
+	if r.Shells > 1 {
+		issues = append(issues, fmt.Sprintf("%d separate shells", r.Shells))
+	}
+	return issues
+}
+
+type vec3 [3]float64
+
+func (a vec3) sub(b vec3) vec3 { return vec3{a[0] - b[0], a[1] - b[1], a[2] - b[2]} }
+
+func (a vec3) cross(b vec3) vec3 {
+	return vec3{a[1]*b[2] - a[2]*b[1], a[2]*b[0] - a[0]*b[2], a[0]*b[1] - a[1]*b[0]}
+}
+
+func (a vec3) dot(b vec3) float64 { return a[0]*b[0] + a[1]*b[1] + a[2]*b[2] }
+
+func (a vec3) length() float64 { return math.Sqrt(a.dot(a)) }
+
+// meshData is an indexed triangle mesh; STL corners are welded by position
+type meshData struct {
+	name  string
+	verts []vec3
+	tris  [][3]int
+	index map[vec3]int
+}
+
+func newMeshData(name string) *meshData {
+	return &meshData{name: name, index: make(map[vec3]int)}
+}
+
+// vertex returns the index of a position, adding it if it's new
+func (m *meshData) vertex(v vec3) int {
+	if i, ok := m.index[v]; ok {
+		return i
+	}
+	m.verts = append(m.verts, v)
+	m.index[v] = len(m.verts) - 1
+	return len(m.verts) - 1
+}
+
+// analyze computes the stats and topology checks of a mesh
+func (m *meshData) analyze() *meshReport {
+	r := &meshReport{Name: m.name, Triangles: len(m.tris)}
+	if len(m.tris) == 0 {
+		return r
+	}
+	for i := 0; i < 3; i++ {
+		r.Min[i], r.Max[i] = math.Inf(1), math.Inf(-1)
+	}
+	for _, v := range m.verts {
+		for i := 0; i < 3; i++ {
+			r.Min[i] = math.Min(r.Min[i], v[i])
+			r.Max[i] = math.Max(r.Max[i], v[i])
+		}
+	}
+
+	// Each edge of a closed, consistently oriented mesh is used by exactly
+	// two triangles, once in each direction
+	type edge struct{ a, b int }
+	type edgeUse struct{ count, forward int }
+	edges := make(map[edge]*edgeUse)
+	parent := make([]int, len(m.verts))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+
+	for _, t := range m.tris {
+		a, b, c := m.verts[t[0]], m.verts[t[1]], m.verts[t[2]]
+		n := b.sub(a).cross(c.sub(a))
+		area := n.length() / 2
+		if t[0] == t[1] || t[1] == t[2] || t[0] == t[2] {
+			r.Degenerate++
+			continue
+		}
+		// Slivers still close the mesh, so their edges count
+		if area < degenerateArea {
+			r.Degenerate++
+		}
+		r.Area += area
+		r.Volume += a.dot(b.cross(c)) / 6
+
+		for k := 0; k < 3; k++ {
+			from, to := t[k], t[(k+1)%3]
+			e, forward := edge{from, to}, 1
+			if from > to {
+				e, forward = edge{to, from}, 0
+			}
+			u := edges[e]
+			if u == nil {
+				u = &edgeUse{}
+				edges[e] = u
+			}
+			u.count++
+			u.forward += forward
+			parent[find(from)] = find(to)
+		}
+	}
+
+	for _, u := range edges {
+		switch {
+		case u.count == 1:
+			r.OpenEdges++
+		case u.count > 2:
+			r.NonManifold++
+		case u.forward != 1:
+			r.Flipped++
+		}
+	}
+
+	shells := make(map[int]bool)
+	for _, t := range m.tris {
+		if t[0] != t[1] && t[1] != t[2] && t[0] != t[2] {
+			shells[find(t[0])] = true
+		}
+	}
+	r.Shells = len(shells)
+	return r
+}
+
+// parseMeshes reads the meshes in an STL or OBJ file. An OBJ file can hold
+// several objects, which are checked one by one.
+func parseMeshes(name string, data []byte) ([]*meshData, error) {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".stl":
+		m, err := parseSTL(name, data)
+		if err != nil {
+			return nil, err
+		}
+		return []*meshData{m}, nil
+	case ".obj":
+		return parseOBJ(name, data)
+	}
+	return nil, fmt.Errorf("%s: only STL and OBJ files can be checked", name)
+}
+
+func parseSTL(name string, data []byte) (*meshData, error) {
+	m := newMeshData(name)
+
+	// Binary STL files may start with "solid" too, so the size decides.
+	// Some exporters pad the file after the last triangle; in an ASCII file
+	// the "count" is made of text bytes and far too large to fit.
+	if len(data) >= 84 {
+		n := int(binary.LittleEndian.Uint32(data[80:84]))
+		if n <= (len(data)-84)/50 {
+			for i := 0; i < n; i++ {
+				rec := data[84+50*i:]
+				var t [3]int
+				for k := 0; k < 3; k++ {
+					var v vec3
+					for j := 0; j < 3; j++ {
+						off := 12 + 12*k + 4*j
+						v[j] = float64(math.Float32frombits(binary.LittleEndian.Uint32(rec[off : off+4])))
+					}
+					t[k] = m.vertex(v)
+				}
+				m.tris = append(m.tris, t)
+			}
+			return m, nil
+		}
+	}
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("solid")) {
+		return nil, fmt.Errorf("%s: not a valid STL file", name)
+	}
+
+	var corners []int
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; sc.Scan(); line++ {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "vertex":
+			v, err := parseVec(fields[1:])
+			if err != nil {
+				return nil, fmt.Errorf("%s line %d: %v", name, line, err)
+			}
+			corners = append(corners, m.vertex(v))
+		case "endfacet":
+			if len(corners) != 3 {
+				return nil, fmt.Errorf("%s line %d: facet with %d vertices", name, line, len(corners))
+			}
+			m.tris = append(m.tris, [3]int{corners[0], corners[1], corners[2]})
+			corners = corners[:0]
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	return m, nil
+}
+
+func parseOBJ(name string, data []byte) ([]*meshData, error) {
+	// Vertex numbers are global across objects; faces are split per object
+	var verts []vec3
+	var meshes []*meshData
+	current := newMeshData(name)
+	local := make(map[int]int)
+
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; sc.Scan(); line++ {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "v":
+			v, err := parseVec(fields[1:])
+			if err != nil {
+				return nil, fmt.Errorf("%s line %d: %v", name, line, err)
+			}
+			verts = append(verts, v)
+		case "o":
+			// Groups ("g") are material or smoothing groups within an
+			// object, not separate meshes
+			if len(current.tris) > 0 {
+				meshes = append(meshes, current)
+			}
+			objName := name
+			if len(fields) > 1 {
+				objName = name + ":" + strings.Join(fields[1:], " ")
+			}
+			if len(current.tris) == 0 {
+				current.name = objName
+			} else {
+				current = newMeshData(objName)
+				local = make(map[int]int)
+			}
+		case "f":
+			var face []int
+			for _, f := range fields[1:] {
+				i, err := strconv.Atoi(strings.SplitN(f, "/", 2)[0])
+				if err != nil {
+					return nil, fmt.Errorf("%s line %d: bad face index %q", name, line, f)
+				}
+				if i < 0 {
+					i = len(verts) + i + 1
+				}
+				if i < 1 || i > len(verts) {
+					return nil, fmt.Errorf("%s line %d: face index %d out of range", name, line, i)
+				}
+				// Welded like STL corners so duplicate vertices don't open seams
+				li, ok := local[i]
+				if !ok {
+					li = current.vertex(verts[i-1])
+					local[i] = li
+				}
+				face = append(face, li)
+			}
+			if len(face) < 3 {
+				return nil, fmt.Errorf("%s line %d: face with %d vertices", name, line, len(face))
+			}
+			for k := 1; k+1 < len(face); k++ {
+				current.tris = append(current.tris, [3]int{face[0], face[k], face[k+1]})
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	if len(current.tris) > 0 || len(meshes) == 0 {
+		meshes = append(meshes, current)
+	}
+	return meshes, nil
+}
+
+func parseVec(fields []string) (vec3, error) {
+	var v vec3
+	if len(fields) < 3 {
+		return v, fmt.Errorf("vertex needs three coordinates")
+	}
+	for i := 0; i < 3; i++ {
+		f, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			return v, fmt.Errorf("bad coordinate %q", fields[i])
+		}
+		v[i] = f
+	}
+	return v, nil
+}
+
+// checkMeshes checks the models in exports (zips or folders) and model
+// files. Like the print handoff, an export's combined OBJ is skipped when
+// it has STL parts.
+func checkMeshes(paths []string) ([]*meshReport, error) {
+	var reports []*meshReport
+	check := func(name string, data []byte) error {
+		meshes, err := parseMeshes(name, data)
+		if err != nil {
+			return err
+		}
+		for _, m := range meshes {
+			reports = append(reports, m.analyze())
+		}
+		return nil
+	}
+
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case info.IsDir():
+			files, err := folderModels(p)
+			if err != nil {
+				return nil, err
+			}
+			for _, f := range files {
+				if strings.EqualFold(filepath.Ext(f), ".3mf") {
+					continue
+				}
+				data, err := os.ReadFile(f)
+				if err != nil {
+					return nil, err
+				}
+				if err := check(filepath.Base(p)+"/"+filepath.Base(f), data); err != nil {
+					return nil, err
+				}
+			}
+		case strings.EqualFold(filepath.Ext(p), ".zip"):
+			if err := checkZip(p, check); err != nil {
+				return nil, err
+			}
+		default:
+			data, err := os.ReadFile(p)
+			if err != nil {
+				return nil, err
+			}
+			if err := check(filepath.Base(p), data); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return reports, nil
+}
+
+func checkZip(zipPath string, check func(string, []byte) error) error {
+	zr, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	var entries []string
+	files := make(map[string]*zip.File)
+	for _, f := range zr.File {
+		name := path.Base(f.Name)
+		if f.FileInfo().IsDir() || !isModel(name) || strings.EqualFold(path.Ext(name), ".3mf") {
+			continue
+		}
+		entries = append(entries, name)
+		files[name] = f
+	}
+	sort.Strings(entries)
+	prefix := strings.TrimSuffix(filepath.Base(zipPath), filepath.Ext(zipPath)) + "/"
+	for _, name := range preferParts(entries) {
+		r, err := files[name].Open()
+		if err != nil {
+			return err
+		}
+		data, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if err := check(prefix+name, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// logMeshChecks reports downloaded meshes that need attention as log events
+func logMeshChecks(paths []string) {
+	reports, err := checkMeshes(paths)
+	if err != nil {
+		logEvent(levelWarn, "Could not check the meshes: %v", err)
+		return
+	}
+	bad := 0
+	for _, r := range reports {
+		if issues := r.repairs(); len(issues) > 0 {
+			bad++
+			logEvent(levelWarn, "%s needs repair before printing: %s", r.Name, strings.Join(issues, ", "))
+		}
+	}
+	if bad == 0 && len(reports) > 0 {
+		logEvent(levelOK, "%d mesh(es) checked: watertight and printable", len(reports))
+	}
+}
+
+// meshRepairs checks a downloaded export and describes the meshes that
+// need repair, printing them as it goes
+func meshRepairs(export string) []string {
+	reports, err := checkMeshes([]string{export})
+	if err != nil {
+		fmt.Printf("  %sWarning: could not check the meshes: %v%s\n", colorYellow, err, colorReset)
+		return nil
+	}
+	var repairs []string
+	for _, r := range reports {
+		if issues := r.repairs(); len(issues) > 0 {
+			repairs = append(repairs, r.Name+": "+strings.Join(issues, ", "))
+			fmt.Printf("  %s! %s needs repair: %s%s\n", colorYellow, r.Name, strings.Join(issues, ", "), colorReset)
+		}
+	}
+	return repairs
+}
+
+// printMeshReports shows the stats of every mesh as a table
+func printMeshReports(reports []*meshReport) {
+	fmt.Printf("%s%-36s %9s %-22s %10s %10s %-10s %s%s\n", colorDim, "Mesh", "Triangles", "Size (mm)", "Vol (cm3)", "Area (cm2)", "Watertight", "Shells", colorReset)
+	for _, r := range reports {
+		size := vec3{r.Max[0] - r.Min[0], r.Max[1] - r.Min[1], r.Max[2] - r.Min[2]}
+		watertight := colorGreen + "yes" + colorReset
+		if !r.watertight() {
+			watertight = colorRed + "no " + colorReset
+		}
+		fmt.Printf("%-36s %9d %-22s %10.2f %10.2f %s        %d\n", truncate(r.Name, 36), r.Triangles,
+			fmt.Sprintf("%.1f x %.1f x %.1f", size[0], size[1], size[2]), math.Abs(r.Volume)/1000, r.Area/100, watertight, r.Shells)
+		if issues := r.repairs(); len(issues) > 0 {
+			fmt.Printf("  %s✗ Needs repair: %s%s\n", colorRed, strings.Join(issues, ", "), colorReset)
+		}
+		if issues := r.warnings(); len(issues) > 0 {
+			fmt.Printf("  %s! %s%s\n", colorYellow, strings.Join(issues, ", "), colorReset)
+		}
+	}
+}
+
+// runCheckCommand is the check subcommand: mesh stats and print readiness
+// of downloaded exports
+func runCheckCommand(args []string) int {
+	fs := flag.NewFlagSet("check", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Println("Usage: slicer-launcher check EXPORT.zip|FOLDER|MODEL.stl|MODEL.obj...")
+	}
+	paths, err := parseArgs(fs, args)
+	if err != nil {
+		return exitUsage
+	}
+	if len(paths) == 0 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	reports, err := checkMeshes(paths)
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitError
+	}
+	if len(reports) == 0 {
+		fmt.Println("No STL or OBJ files found.")
+		return exitError
+	}
+	printMeshReports(reports)
+
+	bad := 0
+	for _, r := range reports {
+		if len(r.repairs()) > 0 {
+			bad++
+		}
+	}
+	fmt.Println()
+	if bad > 0 {
+		fmt.Printf("%s%d of %d meshes need repair before printing%s (e.g. Meshmixer, Blender's 3D Print Toolbox, or the slicer app's repair)\n", colorRed, bad, len(reports), colorReset)
+		return exitError
+	}
+	fmt.Printf("%s✓ All %d meshes are watertight and printable%s\n", colorGreen, len(reports), colorReset)
+	return exitOK
+}
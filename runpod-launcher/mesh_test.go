@@ -0,0 +1,149 @@
+// Copyright (c) 2025-2026 Mik Gangal
+// Licensed under CC BY-NC-SA 4.0 - https://creativecommons.org/licenses/by-nc-sa/4.0/
+
+package main
+
+import (
+	"encoding/binary"
+	"math"
+	"strings"
+	"testing"
+)
+
+// cubeVerts and cubeFaces describe a unit cube with outward normals
+const cubeVerts = `v 0 0 0
+v 1 0 0
+v 1 1 0
+v 0 1 0
+v 0 0 1
+v 1 0 1
+v 1 1 1
+v 0 1 1
+`
+
+var cubeFaces = []string{
+	"f 1 4 3 2",
+	"f 5 6 7 8",
+	"f 1 2 6 5",
+	"f 3 4 8 7",
+	"f 1 5 8 4",
+	"f 2 3 7 6",
+}
+
+// binarySTL writes the triangles of a mesh as a binary STL file followed
+// by padding bytes
+func binarySTL(t *testing.T, m *meshData, padding int) []byte {
+	t.Helper()
+	data := make([]byte, 84, 84+50*len(m.tris)+padding)
+	binary.LittleEndian.PutUint32(data[80:84], uint32(len(m.tris)))
+	for _, tri := range m.tris {
+		rec := make([]byte, 50)
+		for k := 0; k < 3; k++ {
+			for j := 0; j < 3; j++ {
+				off := 12 + 12*k + 4*j
+				binary.LittleEndian.PutUint32(rec[off:off+4], math.Float32bits(float32(m.verts[tri[k]][j])))
+			}
+		}
+		data = append(data, rec...)
+	}
+	return append(data, make([]byte, padding)...)
+}
+
+func TestMeshChecks(t *testing.T) {
+	closed := cubeVerts + strings.Join(cubeFaces, "\n")
+	cube, err := parseOBJ("cube.obj", []byte(closed))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		file       string
+		data       []byte
+		meshes     int
+		triangles  int
+		openEdges  int
+		degenerate int
+		watertight bool
+	}{
+		{
+			name:       "closed cube",
+			file:       "cube.obj",
+			data:       []byte(closed),
+			meshes:     1,
+			triangles:  12,
+			watertight: true,
+		},
+		{
+			name:      "open cube",
+			file:      "open.obj",
+			data:      []byte(cubeVerts + strings.Join(cubeFaces[1:], "\n")),
+			meshes:    1,
+			triangles: 10,
+			openEdges: 4,
+		},
+		{
+			name: "grouped OBJ",
+			file: "grouped.obj",
+			data: []byte("o skull\n" + cubeVerts + "g outer\n" + strings.Join(cubeFaces[:3], "\n") +
+				"\ng inner\n" + strings.Join(cubeFaces[3:], "\n")),
+			meshes:     1,
+			triangles:  12,
+			watertight: true,
+		},
+		{
+			// Vertex 9 sits on the edge between 5 and 6, so the top face
+			// has a zero-area triangle that still closes the mesh
+			name: "closed cube with a sliver",
+			file: "sliver.obj",
+			data: []byte(cubeVerts + "v 0.5 0 1\n" + strings.Join([]string{
+				"f 1 4 3 2", "f 5 9 6 7 8", "f 1 2 6 9 5", "f 3 4 8 7", "f 1 5 8 4", "f 2 3 7 6",
+			}, "\n")),
+			meshes:     1,
+			triangles:  14,
+			degenerate: 1,
+			watertight: true,
+		},
+		{
+			name:       "padded binary STL",
+			file:       "cube.stl",
+			data:       binarySTL(t, cube[0], 16),
+			meshes:     1,
+			triangles:  12,
+			watertight: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meshes, err := parseMeshes(tt.file, tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(meshes) != tt.meshes {
+				t.Fatalf("got %d meshes, want %d", len(meshes), tt.meshes)
+			}
+			r := meshes[0].analyze()
+			if r.Triangles != tt.triangles {
+				t.Errorf("got %d triangles, want %d", r.Triangles, tt.triangles)
+			}
+			if r.OpenEdges != tt.openEdges {
+				t.Errorf("got %d open edges, want %d", r.OpenEdges, tt.openEdges)
+			}
+			if r.Degenerate != tt.degenerate {
+				t.Errorf("got %d degenerate triangles, want %d", r.Degenerate, tt.degenerate)
+			}
+			if r.watertight() != tt.watertight {
+				t.Errorf("watertight = %v, want %v", r.watertight(), tt.watertight)
+			}
+			if tt.watertight {
+				if len(r.repairs()) > 0 {
+					t.Errorf("unexpected repairs: %v", r.repairs())
+				}
+				if math.Abs(r.Volume-1) > 1e-9 {
+					t.Errorf("got volume %g, want 1", r.Volume)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,312 @@
+// Copyright (c) 2025-2026 Mik Gangal
+// Licensed under CC BY-NC-SA 4.0 - https://creativecommons.org/licenses/by-nc-sa/4.0/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// --metrics serves the running session in the Prometheus text format, so
+// launch times and cost can be graphed next to the rest of the lab's
+// infrastructure. Everything is collected from the session's events plus
+// the HTTP clients (see loggingTransport).
+
+// Buckets for API call latencies, in seconds
+var apiLatencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// Phase names from waitForPodReady as metric labels
+var phaseLabels = map[string]string{
+	"Waiting for GPU":     "queue_wait",
+	"Pulling image":       "image_pull",
+	"Starting services":   "service_start",
+	"Configuring network": "network",
+	"Running":             "running",
+	"Desktop ready":       "vnc_ready",
+}
+
+type apiStats struct {
+	requests map[int]int64 // by status code, 0 = no response
+	errors   int64
+	buckets  []int64
+	sum      float64
+	count    int64
+}
+
+type transferStats struct {
+	bytes      int64
+	completed  int64
+	failed     int64
+	throughput float64 // bytes/s of the last completed transfer
+}
+
+type sessionMetrics struct {
+	srv *http.Server
+	url string
+
+	mu            sync.Mutex
+	podID         string
+	gpu           string
+	profile       string
+	lastPhaseEnd  float64
+	phases        map[string]float64
+	readySec      float64
+	retries       int
+	costPerHr     float64
+	balance       float64
+	accountSpend  float64 // spend per hour of every pod on the account
+	haveBalance   bool
+	api           map[string]*apiStats
+	transfers     map[string]*transferStats
+	transferBytes map[string]int64 // bytes seen so far per running transfer
+}
+
+// activeMetrics is set while --metrics is serving; nil otherwise. HTTP
+// requests from other goroutines read it, so it is swapped atomically.
+var activeMetrics atomic.Pointer[sessionMetrics]
+
+// startMetrics serves /metrics on addr (e.g. 127.0.0.1:9464)
+func startMetrics(addr, profile string) (*sessionMetrics, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("could not serve metrics on %s: %w", addr, err)
+	}
+
+	m := &sessionMetrics{
+		profile:       profile,
+		phases:        make(map[string]float64),
+		api:           make(map[string]*apiStats),
+		transfers:     make(map[string]*transferStats),
+		transferBytes: make(map[string]int64),
+	}
+	m.url = fmt.Sprintf("http://%s/metrics", ln.Addr())
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", m.serve)
+	m.srv = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go m.srv.Serve(ln)
+	return m, nil
+}
+
+func (m *sessionMetrics) close() {
+	activeMetrics.CompareAndSwap(m, nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	m.srv.Shutdown(ctx)
+}
+
+func (m *sessionMetrics) handle(ev Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch ev.Type {
+	case evCreated:
+		m.podID, m.gpu = ev.PodID, ev.GPU
+		if ev.CostPerHr > 0 {
+			m.costPerHr = ev.CostPerHr
+		}
+	case evPhaseDone:
+		// ElapsedSec counts from launch, so a phase lasts until the next one ends
+		label, ok := phaseLabels[ev.Phase]
+		if !ok {
+			label = metricLabelName(ev.Phase)
+		}
+		m.phases[label] = ev.ElapsedSec - m.lastPhaseEnd
+		m.lastPhaseEnd = ev.ElapsedSec
+	case evRetry:
+		m.retries++
+		m.lastPhaseEnd = ev.ElapsedSec
+	case evReady:
+		m.readySec = ev.ElapsedSec
+	case evBalance:
+		m.balance, m.accountSpend, m.haveBalance = ev.Balance, ev.CostPerHr, true
+	case evTransfer:
+		t := ev.Transfer
+		stats := m.transfers[t.Direction]
+		if stats == nil {
+			stats = &transferStats{}
+			m.transfers[t.Direction] = stats
+		}
+		key := t.Direction + "/" + t.Name
+		stats.bytes += t.Bytes - m.transferBytes[key]
+		m.transferBytes[key] = t.Bytes
+		switch {
+		case t.Error != "":
+			stats.failed++
+			delete(m.transferBytes, key)
+		case t.Done:
+			stats.completed++
+			if t.Seconds > 0 {
+				stats.throughput = float64(t.Bytes) / t.Seconds
+			}
+			delete(m.transferBytes, key)
+		}
+	}
+}
+
+// recordAPICall is called by loggingTransport for every HTTP request
+func recordAPICall(req *http.Request, d time.Duration, status int, err error) {
+	m := activeMetrics.Load()
+	if m == nil {
+		return
+	}
+	target := apiTarget(req.URL)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stats := m.api[target]
+	if stats == nil {
+		stats = &apiStats{requests: make(map[int]int64), buckets: make([]int64, len(apiLatencyBuckets))}
+		m.api[target] = stats
+	}
+	stats.requests[status]++
+	if err != nil || status >= 400 {
+		stats.errors++
+	}
+	sec := d.Seconds()
+	for i, le := range apiLatencyBuckets {
+		if sec <= le {
+			stats.buckets[i]++
+		}
+	}
+	stats.sum += sec
+	stats.count++
+}
+
+// apiTarget groups requests by what they talk to
+func apiTarget(u *url.URL) string {
+	host := u.Hostname()
+	switch {
+	case host == "api.runpod.io":
+		return "runpod_graphql"
+	case host == "rest.runpod.io":
+		return "runpod_rest"
+	case strings.HasSuffix(host, ".proxy.runpod.net"):
+		return "pod"
+	default:
+		return "other" // webhooks
+	}
+}
+
+// metricLabelName turns a free-form phase name into a label value
+func metricLabelName(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(s)) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case b.Len() > 0 && !strings.HasSuffix(b.String(), "_"):
+			b.WriteByte('_')
+		}
+	}
+	return strings.TrimSuffix(b.String(), "_")
+}
+
+func (m *sessionMetrics) serve(rw http.ResponseWriter, r *http.Request) {
+	rw.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.write(rw)
+}
+
+// write renders all metrics in the Prometheus text exposition format
+func (m *sessionMetrics) write(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	header := func(name, typ, help string) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+	}
+
+	header("slicer_session_info", "gauge", "The running session (always 1).")
+	fmt.Fprintf(w, "slicer_session_info{pod_id=%q,gpu=%q,profile=%q} 1\n", m.podID, m.gpu, m.profile)
+
+	header("slicer_session_elapsed_seconds", "gauge", "Time since the launch started.")
+	fmt.Fprintf(w, "slicer_session_elapsed_seconds %g\n", sessionElapsed())
+
+	header("slicer_phase_duration_seconds", "gauge", "How long each launch phase took (queue_wait, image_pull, service_start, network, running, vnc_ready).")
+	for _, phase := range sortedKeys(m.phases) {
+		fmt.Fprintf(w, "slicer_phase_duration_seconds{phase=%q} %g\n", phase, m.phases[phase])
+	}
+
+	header("slicer_launch_retries_total", "counter", "Pods replaced because they were stuck in the GPU queue or image pull.")
+	fmt.Fprintf(w, "slicer_launch_retries_total %d\n", m.retries)
+
+	if m.readySec > 0 {
+		header("slicer_ready_seconds", "gauge", "Time from launch until the desktop was ready.")
+		fmt.Fprintf(w, "slicer_ready_seconds %g\n", m.readySec)
+	}
+
+	header("slicer_cost_per_hour_dollars", "gauge", "Current hourly cost of the pod.")
+	fmt.Fprintf(w, "slicer_cost_per_hour_dollars %g\n", m.costPerHr)
+
+	if m.haveBalance {
+		header("slicer_balance_dollars", "gauge", "RunPod account balance at the last check.")
+		fmt.Fprintf(w, "slicer_balance_dollars %g\n", m.balance)
+		header("slicer_account_spend_per_hour_dollars", "gauge", "Hourly spend of all pods on the RunPod account at the last check.")
+		fmt.Fprintf(w, "slicer_account_spend_per_hour_dollars %g\n", m.accountSpend)
+	}
+
+	header("slicer_api_requests_total", "counter", "HTTP requests by target and status code (0 = no response).")
+	for _, target := range sortedKeys(m.api) {
+		stats := m.api[target]
+		codes := make([]int, 0, len(stats.requests))
+		for code := range stats.requests {
+			codes = append(codes, code)
+		}
+		sort.Ints(codes)
+		for _, code := range codes {
+			fmt.Fprintf(w, "slicer_api_requests_total{target=%q,code=\"%d\"} %d\n", target, code, stats.requests[code])
+		}
+	}
+
+	header("slicer_api_errors_total", "counter", "HTTP requests that failed or returned a 4xx/5xx status.")
+	for _, target := range sortedKeys(m.api) {
+		fmt.Fprintf(w, "slicer_api_errors_total{target=%q} %d\n", target, m.api[target].errors)
+	}
+
+	header("slicer_api_request_duration_seconds", "histogram", "HTTP request latency by target.")
+	for _, target := range sortedKeys(m.api) {
+		stats := m.api[target]
+		for i, le := range apiLatencyBuckets {
+			fmt.Fprintf(w, "slicer_api_request_duration_seconds_bucket{target=%q,le=\"%g\"} %d\n", target, le, stats.buckets[i])
+		}
+		fmt.Fprintf(w, "slicer_api_request_duration_seconds_bucket{target=%q,le=\"+Inf\"} %d\n", target, stats.count)
+		fmt.Fprintf(w, "slicer_api_request_duration_seconds_sum{target=%q} %g\n", target, stats.sum)
+		fmt.Fprintf(w, "slicer_api_request_duration_seconds_count{target=%q} %d\n", target, stats.count)
+	}
+
+	header("slicer_transfer_bytes_total", "counter", "Bytes uploaded to or downloaded from the pod.")
+	for _, dir := range sortedKeys(m.transfers) {
+		fmt.Fprintf(w, "slicer_transfer_bytes_total{direction=%q} %d\n", dir, m.transfers[dir].bytes)
+	}
+
+	header("slicer_transfers_total", "counter", "Finished transfers by result.")
+	for _, dir := range sortedKeys(m.transfers) {
+		stats := m.transfers[dir]
+		fmt.Fprintf(w, "slicer_transfers_total{direction=%q,result=\"ok\"} %d\n", dir, stats.completed)
+		fmt.Fprintf(w, "slicer_transfers_total{direction=%q,result=\"failed\"} %d\n", dir, stats.failed)
+	}
+
+	header("slicer_transfer_throughput_bytes_per_second", "gauge", "Average speed of the last completed transfer.")
+	for _, dir := range sortedKeys(m.transfers) {
+		fmt.Fprintf(w, "slicer_transfer_throughput_bytes_per_second{direction=%q} %g\n", dir, m.transfers[dir].throughput)
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2025-2026 Mik Gangal
+// Licensed under CC BY-NC-SA 4.0 - https://creativecommons.org/licenses/by-nc-sa/4.0/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// notifyTimeout bounds a notifier command; powershell can take seconds to
+// start and a hung D-Bus call should not pile up processes
+const notifyTimeout = 10 * time.Second
+
+// notifyDesktop shows a native desktop notification (best effort)
+func notifyDesktop(title, message string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+	defer cancel()
+	var cmd *exec.Cmd
+
+	switch runtime.GOOS {
+	case "windows":
+		script := fmt.Sprintf(`[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+$xml = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$text = $xml.GetElementsByTagName('text')
+$text.Item(0).AppendChild($xml.CreateTextNode('%s')) > $null
+$text.Item(1).AppendChild($xml.CreateTextNode('%s')) > $null
+$toast = [Windows.UI.Notifications.ToastNotification]::new($xml)
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('Slicer Launcher').Show($toast)`,
+			psEscape(title), psEscape(message))
+		cmd = exec.CommandContext(ctx, "powershell", "-NoProfile", "-NonInteractive", "-Command", script)
+	case "darwin":
+		script := fmt.Sprintf("display notification %q with title %q", message, title)
+		cmd = exec.CommandContext(ctx, "osascript", "-e", script)
+	default: // Linux and others: notify-send, or the D-Bus notification service directly
+		if _, err := exec.LookPath("notify-send"); err == nil {
+			cmd = exec.CommandContext(ctx, "notify-send", "--app-name=Slicer Launcher", title, message)
+		} else {
+			cmd = exec.CommandContext(ctx, "gdbus", "call", "--session",
+				"--dest", "org.freedesktop.Notifications",
+				"--object-path", "/org/freedesktop/Notifications",
+				"--method", "org.freedesktop.Notifications.Notify",
+				"Slicer Launcher", "0", "", title, message, "[]", "{}", "-1")
+		}
+	}
+
+	return cmd.Run()
+}
+
+// desktopNotifier turns session events into desktop notifications, as
+// enabled in settings.json
+type desktopNotifier struct {
+	settings *Settings
+	watchdog bool // running inside the watchdog, not the launcher
+}
+
+func (n *desktopNotifier) handle(ev Event) {
+	var name, title, message string
+
+	switch ev.Type {
+	case evReady:
+		name, title, message = notifyReady, "Desktop ready", "Your 3D Slicer desktop is ready."
+	case evFileBrowserReady:
+		name, title, message = notifyFileBrowserReady, "File Browser ready", "You can upload files now."
+	case evBudgetThreshold:
+		name, title, message = notifyBudget80, "Session budget 80% used", ev.Message
+		if ev.Percent >= 100 {
+			name, title = notifyBudget100, "Session budget reached"
+		}
+	case evIdle:
+		name, title, message = notifyIdle, "GPU idle", ev.Message
+	case evInterrupted:
+		name, title, message = notifyInterrupted, "Spot pod interrupted", ev.Message
+	case evTerminated:
+		name, title, message = notifyTerminated, "Pod terminated", fmt.Sprintf("Pod %s was terminated.", ev.PodID)
+		if n.watchdog {
+			title = "Pod terminated by watchdog"
+			message = fmt.Sprintf("The launcher stopped responding, so pod %s was terminated.", ev.PodID)
+		}
+	case evTerminateFailed:
+		name, title, message = notifyTerminateFailed, "Pod termination failed",
+			fmt.Sprintf("Pod %s may still be running. Check the RunPod console.", ev.PodID)
+	case evTransfer:
+		if t := ev.Transfer; t.Done && t.Direction == "download" {
+			name, title, message = notifyExportDownloaded, "Download complete",
+				fmt.Sprintf("%s (%s)", t.Name, formatBytes(t.Bytes))
+		}
+	}
+
+	if name == "" || !n.settings.notifies(name) {
+		return
+	}
+	// Failures only show up in the session log; the console shows the same event
+	show := func() {
+		if err := notifyDesktop(title, message); err != nil {
+			slog.Debug("desktop notification failed", "title", title, "error", err)
+		}
+	}
+	// emit waits for every sink, so the launcher doesn't wait for the
+	// notifier. The watchdog exits right after, so it waits.
+	if n.watchdog {
+		show()
+		return
+	}
+	go show()
+}
+
+// psEscape escapes a string for a single-quoted PowerShell literal
+func psEscape(s string) string {
+	return strings.ReplaceAll(s, "'", "''")
+}
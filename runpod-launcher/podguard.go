@@ -0,0 +1,127 @@
+// Copyright (c) 2025-2026 Mik Gangal
+// Licensed under CC BY-NC-SA 4.0 - https://creativecommons.org/licenses/by-nc-sa/4.0/
+
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Pod-side safety net: the launcher passes a max lifetime and a heartbeat
+// token to the pod via environment variables. /usr/local/bin/auto-terminate
+// on the pod terminates it when the lifetime is up or the heartbeats stop,
+// so a laptop losing power can't leave a pod billing forever.
+
+const (
+	podHeartbeatFile     = "/tmp/slicer-heartbeat"
+	podHeartbeatInterval = time.Minute
+
+	defaultMaxHours         = 12
+	defaultHeartbeatTimeout = 30 * time.Minute
+)
+
+// podGuard holds the settings injected into the pod
+type podGuard struct {
+	token            string
+	maxLifetime      time.Duration
+	heartbeatTimeout time.Duration
+
+	mu       sync.Mutex
+	deadline time.Time
+}
+
+func newPodGuard(maxLifetime, heartbeatTimeout time.Duration) (*podGuard, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("could not generate heartbeat token: %w", err)
+	}
+	token := hex.EncodeToString(buf)
+	addSecret(token)
+	return &podGuard{
+		token:            token,
+		maxLifetime:      maxLifetime,
+		heartbeatTimeout: heartbeatTimeout,
+	}, nil
+}
+
+// env returns the environment variables read by auto-terminate on the pod
+func (g *podGuard) env() map[string]string {
+	return map[string]string{
+		"SLICER_MAX_LIFETIME_MIN":      strconv.Itoa(int(g.maxLifetime.Minutes())),
+		"SLICER_HEARTBEAT_TIMEOUT_MIN": strconv.Itoa(int(g.heartbeatTimeout.Minutes())),
+		"SLICER_HEARTBEAT_FILE":        podHeartbeatFile,
+		"SLICER_HEARTBEAT_TOKEN":       g.token,
+	}
+}
+
+// deadlineEnv is the environment for a pod that ends itself at a fixed
+// time without heartbeats, for pods no launcher session looks after
+func deadlineEnv(end time.Time) (map[string]string, error) {
+	guard, err := newPodGuard(time.Until(end), 0)
+	if err != nil {
+		return nil, err
+	}
+	env := guard.env()
+	env["SLICER_DEADLINE"] = strconv.FormatInt(end.Unix(), 10)
+	return env, nil
+}
+
+// heartbeat writes "<token> <deadline>" to the pod. The deadline lets the
+// launcher move the max lifetime while the session is running.
+func (g *podGuard) heartbeat(fb *fileBrowserClient) error {
+	var deadline int64
+	if d := g.currentDeadline(); !d.IsZero() {
+		deadline = d.Unix()
+	}
+	return fb.writeFile(podHeartbeatFile, []byte(fmt.Sprintf("%s %d\n", g.token, deadline)))
+}
+
+// currentDeadline returns when the pod shuts itself down (zero if unlimited)
+func (g *podGuard) currentDeadline() time.Time {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.deadline
+}
+
+// extend moves the deadline d into the future, starting from now if it already passed
+func (g *podGuard) extend(d time.Duration) time.Time {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	base := g.deadline
+	if base.IsZero() || base.Before(time.Now()) {
+		base = time.Now()
+	}
+	g.deadline = base.Add(d)
+	return g.deadline
+}
+
+// startPodHeartbeat keeps the pod's heartbeat file fresh until stop is closed
+func startPodHeartbeat(g *podGuard, fb *fileBrowserClient, stop <-chan struct{}) {
+	go func() {
+		ticker := time.NewTicker(podHeartbeatInterval)
+		defer ticker.Stop()
+		failures := 0
+		for {
+			if err := g.heartbeat(fb); err != nil {
+				failures++
+				// Only warn once the pod is getting close to giving up on us
+				if g.heartbeatTimeout > 0 && time.Duration(failures)*podHeartbeatInterval >= g.heartbeatTimeout/2 {
+					logEvent(levelWarn, "Pod heartbeat failing (%v) - pod may shut itself down", err)
+				}
+			} else {
+				failures = 0
+			}
+
+			select {
+			case <-ticker.C:
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
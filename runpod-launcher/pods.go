@@ -0,0 +1,399 @@
+// Copyright (c) 2025-2026 Mik Gangal
+// Licensed under CC BY-NC-SA 4.0 - https://creativecommons.org/licenses/by-nc-sa/4.0/
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The list, terminate and stop subcommands work on every pod in the
+// account, so orphaned pods can be found and cleaned up without the
+// RunPod console. Pods this launcher created are marked as "mine".
+
+// launcherPodName matches the names runSession gives its pods. Other
+// launchers on the same account name theirs the same way, so a match is
+// only shown as a hint and never makes a pod "mine".
+var launcherPodName = regexp.MustCompile(`^slicer-([A-Za-z0-9][A-Za-z0-9_.-]*-)?\d+$`)
+
+// PodSummary is one pod from the account's pod list
+type PodSummary struct {
+	ID              string  `json:"id"`
+	Name            string  `json:"name"`
+	DesiredStatus   string  `json:"desiredStatus"`
+	CostPerHr       float64 `json:"costPerHr"`
+	TemplateID      string  `json:"templateId"`
+	NetworkVolumeID string  `json:"networkVolumeId"`
+	Image           string  `json:"image"`
+	LastStartedAt   string  `json:"lastStartedAt"`
+	Machine         Machine `json:"machine"`
+
+	// Filled in by the launcher
+	Mine          bool   `json:"mine"`
+	Source        string `json:"source,omitempty"`        // "launcher", "fleet <name>" or "schedule <entry>"
+	LauncherStyle bool   `json:"launcherStyle,omitempty"` // named like a launcher pod, but not known to this one
+}
+
+// uptime is how long a running pod has been up (0 if unknown)
+func (p PodSummary) uptime() time.Duration {
+	if p.DesiredStatus != "RUNNING" || p.LastStartedAt == "" {
+		return 0
+	}
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999 -0700 MST"} {
+		if t, err := time.Parse(layout, p.LastStartedAt); err == nil {
+			return time.Since(t)
+		}
+	}
+	return 0
+}
+
+// listPods returns all pods in the account
+func listPods(apiKey string) ([]PodSummary, error) {
+	req, err := http.NewRequest("GET", runpodAPIURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not create request: %w", err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
+
+	client := newHTTPClient(30 * time.Second)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, string(body))
+	}
+
+	var pods []PodSummary
+	if err := json.Unmarshal(body, &pods); err != nil {
+		return nil, fmt.Errorf("could not parse response: %w", err)
+	}
+
+	sources := launcherPodSources()
+	for i := range pods {
+		if src, ok := sources[pods[i].ID]; ok {
+			pods[i].Mine, pods[i].Source = true, src
+		} else if launcherPodName.MatchString(pods[i].Name) {
+			pods[i].LauncherStyle = true
+		}
+	}
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+	return pods, nil
+}
+
+// launcherPodSources maps the IDs of pods the launcher knows it created -
+// from state.json, the saved fleets and the schedule - to where they came from
+func launcherPodSources() map[string]string {
+	sources := make(map[string]string)
+	if state, err := loadState(); err == nil {
+		for _, p := range state.Pods {
+			sources[p.ID] = "launcher"
+		}
+	}
+	names, _ := listFleets()
+	for _, name := range names {
+		f, err := loadFleet(name)
+		if err != nil || f == nil {
+			continue
+		}
+		for _, s := range f.Seats {
+			if s.PodID != "" {
+				sources[s.PodID] = "fleet " + name
+			}
+		}
+	}
+	for podID, entry := range scheduledPods() {
+		sources[podID] = "schedule " + entry
+	}
+	return sources
+}
+
+// parseArgs parses flags that may come before or after the positional
+// arguments ("terminate abc123 -yes") and returns the positional ones
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var rest []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return rest, nil
+		}
+		rest = append(rest, args[0])
+		args = args[1:]
+	}
+}
+
+func runList(args []string) int {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	mine := fs.Bool("mine", false, "only pods this launcher created")
+	status := fs.String("status", "", "only pods with this status (RUNNING, EXITED, ...)")
+	gpu := fs.String("gpu", "", "only pods whose GPU contains this text")
+	name := fs.String("name", "", "only pods whose name contains this text")
+	asJSON := fs.Bool("json", false, "print the pods as JSON")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	apiKey, err := commandSetup()
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitUsage
+	}
+	pods, err := listPods(apiKey)
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitError
+	}
+
+	var selected []PodSummary
+	for _, p := range pods {
+		switch {
+		case *mine && !p.Mine:
+		case *status != "" && !strings.EqualFold(p.DesiredStatus, *status):
+		case *gpu != "" && !strings.Contains(strings.ToLower(p.Machine.GpuDisplayName), strings.ToLower(*gpu)):
+		case *name != "" && !strings.Contains(strings.ToLower(p.Name), strings.ToLower(*name)):
+		default:
+			selected = append(selected, p)
+		}
+	}
+
+	if *asJSON {
+		if selected == nil {
+			selected = []PodSummary{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(selected)
+		return exitOK
+	}
+
+	if len(selected) == 0 {
+		fmt.Println("No pods.")
+		return exitOK
+	}
+	printPods(selected)
+
+	running, perHr := 0, 0.0
+	for _, p := range selected {
+		if p.DesiredStatus == "RUNNING" {
+			running++
+			perHr += p.CostPerHr
+		}
+	}
+	fmt.Printf("\n%d pods, %d running │ %s$%.2f/hr%s\n", len(selected), running, colorRed, perHr, colorReset)
+	return exitOK
+}
+
+func printPods(pods []PodSummary) {
+	fmt.Printf("%s%-16s %-22s %-22s %-10s %9s %8s %-12s %s%s\n", colorDim,
+		"ID", "Name", "GPU", "Status", "Uptime", "$/hr", "Template", "Created by", colorReset)
+	for _, p := range pods {
+		uptime := "-"
+		if d := p.uptime(); d > 0 {
+			uptime = formatDuration(d.Truncate(time.Minute))
+		}
+		color := colorReset
+		if p.DesiredStatus == "RUNNING" {
+			color = colorGreen
+		}
+		fmt.Printf("%-16s %-22s %-22s %s%-10s%s %9s %8.2f %-12s %s\n",
+			p.ID, truncate(p.Name, 22), truncate(p.Machine.GpuDisplayName, 22), color, p.DesiredStatus, colorReset,
+			uptime, p.CostPerHr, p.TemplateID, p.createdBy())
+	}
+}
+
+// createdBy is the "Created by" column: where the launcher knows the pod
+// from, or a hint for pods another launcher probably created
+func (p PodSummary) createdBy() string {
+	if p.LauncherStyle {
+		return colorDim + "launcher-style (not this one)" + colorReset
+	}
+	return p.Source
+}
+
+// truncate shortens s to n characters for table columns
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
+
+// findPods returns the listed pods with the given IDs
+func findPods(pods []PodSummary, ids []string) ([]PodSummary, error) {
+	byID := make(map[string]PodSummary, len(pods))
+	for _, p := range pods {
+		byID[p.ID] = p
+	}
+	var found []PodSummary
+	for _, id := range ids {
+		p, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("no pod %s in this account", id)
+		}
+		found = append(found, p)
+	}
+	return found, nil
+}
+
+func runTerminate(args []string) int {
+	fs := flag.NewFlagSet("terminate", flag.ContinueOnError)
+	allMine := fs.Bool("all-mine", false, "terminate every pod this launcher created")
+	yes := fs.Bool("yes", false, "terminate without asking for confirmation")
+	ids, err := parseArgs(fs, args)
+	if err != nil {
+		return exitUsage
+	}
+	if *allMine == (len(ids) > 0) {
+		fmt.Println("usage: slicer-launcher terminate <pod-id>... | --all-mine [-yes]")
+		return exitUsage
+	}
+
+	apiKey, err := commandSetup()
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitUsage
+	}
+	pods, err := listPods(apiKey)
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitError
+	}
+
+	var targets []PodSummary
+	if *allMine {
+		for _, p := range pods {
+			if p.Mine && p.DesiredStatus != "TERMINATED" {
+				targets = append(targets, p)
+			}
+		}
+		if len(targets) == 0 {
+			fmt.Println("No pods created by this launcher.")
+			return exitOK
+		}
+	} else if targets, err = findPods(pods, ids); err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitError
+	}
+
+	printPods(targets)
+	fmt.Println()
+	for _, p := range targets {
+		if !p.Mine {
+			fmt.Printf("%sWarning: %s was not created by this launcher%s\n", colorYellow, p.ID, colorReset)
+		}
+	}
+	if !*yes && !confirm(fmt.Sprintf("Terminate %d pod(s)? Their disks are deleted. (y/n): ", len(targets))) {
+		return exitOK
+	}
+
+	var podIDs []string
+	for _, p := range targets {
+		podIDs = append(podIDs, p.ID)
+	}
+	failed := deletePods(apiKey, podIDs, func(podID string) {
+		fmt.Printf("  %s✓%s Pod %s terminated\n", colorGreen, colorReset, podID)
+	})
+	if len(failed) > 0 {
+		return exitError
+	}
+	return exitOK
+}
+
+// deletePods terminates pods in parallel and calls done for each one that is
+// confirmed gone. It returns the pods whose termination is unconfirmed.
+func deletePods(apiKey string, ids []string, done func(podID string)) []string {
+	var failed []string
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, id := range ids {
+		wg.Add(1)
+		go func(podID string) {
+			defer wg.Done()
+			if err := deletePod(apiKey, podID); err != nil {
+				mu.Lock()
+				failed = append(failed, podID)
+				mu.Unlock()
+				fmt.Printf("  %s✗%s %s: %v\n", colorRed, colorReset, podID, err)
+				reportTerminationFailure(apiKey, podID, err)
+				return
+			}
+			done(podID)
+		}(id)
+	}
+	wg.Wait()
+
+	if len(failed) > 0 {
+		fmt.Printf("%sCould not confirm termination of %s - check https://www.runpod.io/console/pods%s\n",
+			colorRed, strings.Join(failed, ", "), colorReset)
+	}
+	return failed
+}
+
+func runStop(args []string) int {
+	fs := flag.NewFlagSet("stop", flag.ContinueOnError)
+	yes := fs.Bool("yes", false, "stop without asking for confirmation")
+	ids, err := parseArgs(fs, args)
+	if err != nil {
+		return exitUsage
+	}
+	if len(ids) == 0 {
+		fmt.Println("usage: slicer-launcher stop <pod-id>... [-yes]")
+		return exitUsage
+	}
+
+	apiKey, err := commandSetup()
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitUsage
+	}
+	pods, err := listPods(apiKey)
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitError
+	}
+	targets, err := findPods(pods, ids)
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitError
+	}
+
+	printPods(targets)
+	fmt.Println()
+	if !*yes && !confirm(fmt.Sprintf("Stop %d pod(s)? GPU billing ends, their disks keep billing until terminated. (y/n): ", len(targets))) {
+		return exitOK
+	}
+
+	code := exitOK
+	for _, p := range targets {
+		if err := stopPod(apiKey, p.ID); err != nil {
+			fmt.Printf("  %s✗%s %s: %v\n", colorRed, colorReset, p.ID, err)
+			code = exitError
+			continue
+		}
+		// Pods the launcher tracks keep their record, now as stopped
+		if p.Source == "launcher" {
+			if err := setPodStatus(p.ID, podStatusStopped); err != nil {
+				fmt.Printf("%sWarning: could not update launcher state: %v%s\n", colorYellow, err, colorReset)
+			}
+		}
+		fmt.Printf("  %s✓%s Pod %s stopped\n", colorGreen, colorReset, p.ID)
+	}
+	return code
+}
@@ -0,0 +1,431 @@
+// Copyright (c) 2025-2026 Mik Gangal
+// Licensed under CC BY-NC-SA 4.0 - https://creativecommons.org/licenses/by-nc-sa/4.0/
+
+package main
+
+import (
+	"archive/zip"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// Local print handoff: downloaded exports are opened in a slicer app
+// installed on this computer, with the printer's profiles loaded, and/or
+// copied to a folder a print server watches. Nobody has to log in to a
+// printer cloud account inside a rented pod.
+
+// Slicer apps the launcher knows how to start
+const (
+	appOrcaSlicer  = "orcaslicer"
+	appPrusaSlicer = "prusaslicer"
+	appBambuStudio = "bambustudio"
+)
+
+// PrinterConfig is a printer from settings.json
+type PrinterConfig struct {
+	// App opens the models in a local slicer app; AppPath overrides where
+	// it is installed
+	App     string `json:"app,omitempty"`
+	AppPath string `json:"appPath,omitempty"`
+
+	// Profiles are the app's printer/process/filament profiles to load:
+	// .ini bundles for PrusaSlicer, .json presets for OrcaSlicer and Bambu
+	// Studio (machine and process first, then filaments)
+	Profiles []string `json:"profiles,omitempty"`
+
+	// Folder is a watched (network) print folder the models are copied to
+	Folder string `json:"folder,omitempty"`
+}
+
+func (p PrinterConfig) validate() error {
+	switch p.App {
+	case "", appOrcaSlicer, appPrusaSlicer, appBambuStudio:
+	default:
+		return fmt.Errorf("app must be %q, %q or %q", appOrcaSlicer, appPrusaSlicer, appBambuStudio)
+	}
+	if p.App == "" && p.Folder == "" {
+		return fmt.Errorf("needs an app, a folder or both")
+	}
+	return nil
+}
+
+// printer returns the named printer from settings.json
+func (s *Settings) printer(name string) (PrinterConfig, error) {
+	p, ok := s.Printers[name]
+	if !ok {
+		var names []string
+		for n := range s.Printers {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			return p, fmt.Errorf("unknown printer %q (add printers to %s)", name, settingsFileName)
+		}
+		return p, fmt.Errorf("unknown printer %q (have: %s)", name, strings.Join(names, ", "))
+	}
+	if err := p.validate(); err != nil {
+		return p, fmt.Errorf("printer %q: %w", name, err)
+	}
+	return p, nil
+}
+
+// appLocations are where the slicer apps are usually installed
+func appLocations(app string) []string {
+	switch runtime.GOOS {
+	case "windows":
+		return map[string][]string{
+			appOrcaSlicer:  {`C:\Program Files\OrcaSlicer\orca-slicer.exe`},
+			appPrusaSlicer: {`C:\Program Files\Prusa3D\PrusaSlicer\prusa-slicer.exe`},
+			appBambuStudio: {`C:\Program Files\Bambu Studio\bambu-studio.exe`},
+		}[app]
+	case "darwin":
+		return map[string][]string{
+			appOrcaSlicer:  {"/Applications/OrcaSlicer.app/Contents/MacOS/OrcaSlicer"},
+			appPrusaSlicer: {"/Applications/PrusaSlicer.app/Contents/MacOS/PrusaSlicer", "/Applications/Original Prusa Drivers/PrusaSlicer.app/Contents/MacOS/PrusaSlicer"},
+			appBambuStudio: {"/Applications/BambuStudio.app/Contents/MacOS/BambuStudio"},
+		}[app]
+	}
+	return map[string][]string{
+		appOrcaSlicer:  {"orca-slicer", "OrcaSlicer"},
+		appPrusaSlicer: {"prusa-slicer", "PrusaSlicer"},
+		appBambuStudio: {"bambu-studio", "BambuStudio"},
+	}[app]
+}
+
+// findApp returns the executable of a slicer app
+func findApp(p PrinterConfig) (string, error) {
+	if p.AppPath != "" {
+		if _, err := os.Stat(p.AppPath); err != nil {
+			return "", fmt.Errorf("appPath: %w", err)
+		}
+		return p.AppPath, nil
+	}
+	for _, loc := range appLocations(p.App) {
+		if filepath.IsAbs(loc) {
+			if _, err := os.Stat(loc); err == nil {
+				return loc, nil
+			}
+		} else if found, err := exec.LookPath(loc); err == nil {
+			return found, nil
+		}
+	}
+	return "", fmt.Errorf("%s is not installed (set appPath for the printer in %s)", p.App, settingsFileName)
+}
+
+// appArgs builds the command line that opens models with the printer's profiles
+func appArgs(p PrinterConfig, models []string) []string {
+	var args []string
+	if len(p.Profiles) > 0 {
+		switch p.App {
+		case appPrusaSlicer:
+			for _, f := range p.Profiles {
+				args = append(args, "--load", f)
+			}
+		default:
+			// Orca and Bambu take machine/process presets and filaments separately
+			var settings, filaments []string
+			for _, f := range p.Profiles {
+				if strings.Contains(strings.ToLower(filepath.Base(f)), "filament") {
+					filaments = append(filaments, f)
+				} else {
+					settings = append(settings, f)
+				}
+			}
+			if len(settings) > 0 {
+				args = append(args, "--load-settings", strings.Join(settings, ";"))
+			}
+			if len(filaments) > 0 {
+				args = append(args, "--load-filaments", strings.Join(filaments, ";"))
+			}
+		}
+	}
+	return append(args, models...)
+}
+
+// isModel reports whether a file is something a slicer app opens
+func isModel(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".stl", ".obj", ".3mf":
+		return true
+	}
+	return false
+}
+
+// printModels turns downloaded exports (zips or folders) and model files
+// into the model files to print. Zips are extracted next to themselves.
+// The combined OBJ is only used when an export has no STL files, so parts
+// aren't loaded twice.
+func printModels(paths []string) ([]string, error) {
+	var models []string
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		var found []string
+		switch {
+		case info.IsDir():
+			found, err = folderModels(p)
+		case strings.EqualFold(filepath.Ext(p), ".zip"):
+			found, err = extractModels(p)
+		case isModel(p):
+			found = []string{p}
+		default:
+			err = fmt.Errorf("%s is not an export or a model file", p)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(found) == 0 {
+			return nil, fmt.Errorf("no models in %s", p)
+		}
+		models = append(models, found...)
+	}
+	return models, nil
+}
+
+func folderModels(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, e := range entries {
+		if !e.IsDir() && isModel(e.Name()) {
+			files = append(files, filepath.Join(dir, e.Name()))
+		}
+	}
+	return preferParts(files), nil
+}
+
+// preferParts drops OBJ files when there are STL parts
+func preferParts(files []string) []string {
+	var parts []string
+	for _, f := range files {
+		if !strings.EqualFold(filepath.Ext(f), ".obj") {
+			parts = append(parts, f)
+		}
+	}
+	if len(parts) == 0 {
+		return files
+	}
+	return parts
+}
+
+// extractModels unpacks the model files of an export zip into a folder
+// of the same name
+func extractModels(zipPath string) ([]string, error) {
+	zr, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	dir := strings.TrimSuffix(zipPath, filepath.Ext(zipPath))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, f := range zr.File {
+		// Only the file name is used, so entries can't point outside dir
+		name := path.Base(f.Name)
+		if f.FileInfo().IsDir() || !isModel(name) {
+			continue
+		}
+		dest := filepath.Join(dir, name)
+		if err := extractFile(f, dest); err != nil {
+			return nil, fmt.Errorf("extracting %s: %w", name, err)
+		}
+		files = append(files, dest)
+	}
+	sort.Strings(files)
+	return preferParts(files), nil
+}
+
+func extractFile(f *zip.File, dest string) error {
+	r, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	return writeFileFrom(dest, r)
+}
+
+func writeFileFrom(dest string, r io.Reader) error {
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, r); err != nil {
+		out.Close()
+		os.Remove(dest)
+		return err
+	}
+	return out.Close()
+}
+
+// handOff sends models to a printer: copies them to its watched folder
+// and opens them in its slicer app
+func handOff(name string, p PrinterConfig, models []string) error {
+	var errs []string
+	if p.Folder != "" {
+		copied := 0
+		for _, m := range models {
+			// Exports all call their parts the same; keep the export's name
+			dest := filepath.Join(p.Folder, filepath.Base(filepath.Dir(m))+"-"+filepath.Base(m))
+			if err := copyModel(m, dest); err != nil {
+				errs = append(errs, fmt.Sprintf("copying %s: %v", filepath.Base(m), err))
+				continue
+			}
+			copied++
+		}
+		if copied > 0 {
+			logEvent(levelOK, "Copied %d model(s) to %s", copied, p.Folder)
+		}
+	}
+	if p.App != "" {
+		exe, err := findApp(p)
+		if err == nil {
+			err = exec.Command(exe, appArgs(p, models)...).Start()
+		}
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("opening %s: %v", p.App, err))
+		} else {
+			logEvent(levelOK, "Opened %d model(s) in %s for %s", len(models), p.App, name)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func copyModel(src, dest string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	return writeFileFrom(dest, in)
+}
+
+// printExports hands downloaded exports to the printer named in settings
+// (printExports), if there is one
+func printExports(paths []string) {
+	name := appSettings.PrintExports
+	if name == "" || len(paths) == 0 {
+		return
+	}
+	if err := printTo(name, paths); err != nil {
+		logEvent(levelError, "Print handoff to %s failed: %v", name, err)
+	}
+}
+
+func printTo(name string, paths []string) error {
+	p, err := appSettings.printer(name)
+	if err != nil {
+		return err
+	}
+	models, err := printModels(paths)
+	if err != nil {
+		return err
+	}
+	return handOff(name, p, models)
+}
+
+// commandLogPrinter shows log events of subcommands without a console
+func commandLogPrinter(ev Event) {
+	if ev.Type != evLog {
+		return
+	}
+	switch ev.Level {
+	case levelOK:
+		fmt.Printf("  %s✓%s %s\n", colorGreen, colorReset, ev.Message)
+	case levelWarn:
+		fmt.Printf("%sWarning: %s%s\n", colorYellow, ev.Message, colorReset)
+	case levelError:
+		fmt.Printf("%sError: %s%s\n", colorRed, ev.Message, colorReset)
+	default:
+		fmt.Println(ev.Message)
+	}
+}
+
+// runPrintCommand is the print subcommand: hand exports or model files to
+// a configured printer
+func runPrintCommand(args []string) int {
+	fs := flag.NewFlagSet("print", flag.ContinueOnError)
+	printerName := fs.String("printer", "", "printer from settings.json (default: printExports, or the only printer)")
+	list := fs.Bool("list", false, "list the configured printers")
+	fs.Usage = func() {
+		fmt.Println("Usage: slicer-launcher print [-printer NAME] EXPORT.zip|FOLDER|MODEL...")
+		fs.PrintDefaults()
+	}
+	paths, err := parseArgs(fs, args)
+	if err != nil {
+		return exitUsage
+	}
+	settings, err := loadSettings()
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitUsage
+	}
+	appSettings = settings
+
+	if *list {
+		if len(settings.Printers) == 0 {
+			fmt.Printf("No printers. Add them to \"printers\" in %s.\n", settingsFileName)
+			return exitOK
+		}
+		var names []string
+		for n := range settings.Printers {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		for _, n := range names {
+			p := settings.Printers[n]
+			var to []string
+			if p.App != "" {
+				to = append(to, p.App)
+			}
+			if p.Folder != "" {
+				to = append(to, p.Folder)
+			}
+			fmt.Printf("%-20s %s\n", n, strings.Join(to, " + "))
+		}
+		return exitOK
+	}
+
+	if len(paths) == 0 {
+		fs.Usage()
+		return exitUsage
+	}
+	name := *printerName
+	if name == "" {
+		name = settings.PrintExports
+	}
+	if name == "" && len(settings.Printers) == 1 {
+		for n := range settings.Printers {
+			name = n
+		}
+	}
+	if name == "" {
+		fmt.Println("Pick a printer with -printer (see 'slicer-launcher print -list')")
+		return exitUsage
+	}
+
+	subscribe(commandLogPrinter)
+	logMeshChecks(paths)
+	if err := printTo(name, paths); err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitError
+	}
+	return exitOK
+}
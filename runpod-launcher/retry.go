@@ -0,0 +1,233 @@
+// Copyright (c) 2025-2026 Mik Gangal
+// Licensed under CC BY-NC-SA 4.0 - https://creativecommons.org/licenses/by-nc-sa/4.0/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// A launch can get stuck before the pod ever runs: in the GPU queue of a
+// busy data center, or pulling the image on a slow host. Each phase has a
+// timeout with a policy - a pod stuck in the queue is replaced on the next
+// GPU type or data center, a stalled image pull gets a fresh pod - and the
+// launch deadline caps all attempts together.
+
+const (
+	defaultQueueTimeout  = 5 * time.Minute
+	defaultPullTimeout   = 10 * time.Minute
+	defaultLaunchTimeout = 20 * time.Minute
+	maxPullRetries       = 2
+)
+
+// Phases of waitForPodReady that have a timeout
+const (
+	phaseWaitingForGPU = "Waiting for GPU"
+	phasePullingImage  = "Pulling image"
+)
+
+// launchLimits bound how long a launch may take
+type launchLimits struct {
+	queue    time.Duration // in "Waiting for GPU" (0 = no limit)
+	pull     time.Duration // in "Pulling image" (0 = no limit)
+	deadline time.Time     // the whole launch, across retries
+}
+
+func (l launchLimits) expired() bool {
+	return !l.deadline.IsZero() && time.Now().After(l.deadline)
+}
+
+// check returns a *stuckError once the pod has been in phase too long
+func (l launchLimits) check(phase string, in time.Duration) error {
+	switch {
+	case phase == phaseWaitingForGPU && l.queue > 0 && in > l.queue:
+	case phase == phasePullingImage && l.pull > 0 && in > l.pull:
+	default:
+		return nil
+	}
+	return &stuckError{phase: phase, after: in}
+}
+
+// stuckError means the pod should be replaced rather than waited for
+type stuckError struct {
+	phase string
+	after time.Duration
+}
+
+func (e *stuckError) Error() string {
+	return fmt.Sprintf("stuck in %q for %s", e.phase, formatDuration(e.after))
+}
+
+// launchPlan decides what each attempt asks RunPod for. GPU types are tried
+// in the profile's order within a data center before moving to the next one.
+type launchPlan struct {
+	profile     Profile
+	gpuTypes    []string // left to try in the current data center
+	dataCenters []string // left to try; empty = wherever RunPod picks
+	pullRetries int
+}
+
+func newLaunchPlan(p Profile) *launchPlan {
+	return &launchPlan{profile: p, gpuTypes: p.GPUTypes, dataCenters: p.DataCenters}
+}
+
+// current returns the profile for the next attempt
+func (lp *launchPlan) current() Profile {
+	p := lp.profile
+	p.GPUTypes = lp.gpuTypes
+	p.DataCenters = lp.dataCenters
+	return p
+}
+
+// next moves on after a failed attempt and describes the decision. failure
+// is a *stuckError, or the error from creating the pod. gpu and dataCenter
+// are where the failed pod landed, if known.
+func (lp *launchPlan) next(failure error, gpu, dataCenter string) (string, bool) {
+	if stuck, ok := failure.(*stuckError); ok && stuck.phase == phasePullingImage {
+		if lp.pullRetries >= maxPullRetries {
+			return "", false
+		}
+		lp.pullRetries++
+		return fmt.Sprintf("Image pull stalled - recreating the pod (%d/%d)", lp.pullRetries, maxPullRetries), true
+	}
+
+	// Stuck in the queue: the next GPU type, in the same data center
+	if _, ok := failure.(*stuckError); ok && len(lp.gpuTypes) > 0 {
+		if !containsGPU(lp.gpuTypes, gpu) {
+			gpu = lp.gpuTypes[0]
+		}
+		if rest := withoutGPU(lp.gpuTypes, gpu); len(rest) > 0 {
+			lp.gpuTypes = rest
+			return fmt.Sprintf("No %s free - trying %s", gpu, strings.Join(rest, ", ")), true
+		}
+	}
+
+	// Nothing left here (or the pod couldn't be created): the next data center
+	if len(lp.dataCenters) > 1 {
+		lp.dataCenters = without(lp.dataCenters, dataCenter)
+		lp.gpuTypes = lp.profile.GPUTypes
+		return fmt.Sprintf("Trying data center %s", strings.Join(lp.dataCenters, ", ")), true
+	}
+	return "", false
+}
+
+// canReplace reports whether a pod stuck in the GPU queue has another GPU
+// type or data center to move to; if not, it keeps its place in the queue
+func (lp *launchPlan) canReplace() bool {
+	return len(lp.gpuTypes) > 1 || len(lp.dataCenters) > 1
+}
+
+// forPlan drops the queue timeout when the plan has nowhere else to go, so
+// the pod waits for a GPU until the deadline instead
+func (l launchLimits) forPlan(lp *launchPlan) launchLimits {
+	if !lp.canReplace() {
+		l.queue = 0
+	}
+	return l
+}
+
+// launchReady creates a pod and waits for its desktop without a session
+// around it (fleets, scheduled launches, batch runs) and returns it with its
+// public TCP ports. Stuck pods are replaced as planned; newEnv gives each
+// attempt its environment and created is told about every pod. When it
+// fails, the last pod has been terminated - or reported, if that could not
+// be confirmed.
+func launchReady(apiKey, name string, profile Profile, limits launchLimits,
+	newEnv func() (map[string]string, error), created func(*PodResponse)) (*PodResponse, map[int]PortInfo, error) {
+	plan := newLaunchPlan(profile)
+	for attempt := 1; ; attempt++ {
+		env, err := newEnv()
+		if err != nil {
+			return nil, nil, err
+		}
+		pod, err := launchPod(apiKey, name, plan.current(), env)
+		if err != nil {
+			decision, retry := "", false
+			if attempt > 1 && !limits.expired() {
+				decision, retry = plan.next(err, "", "")
+			}
+			if !retry {
+				return nil, nil, fmt.Errorf("launching pod: %w", err)
+			}
+			emit(Event{Type: evRetry, Message: fmt.Sprintf("Could not create %s (%v). %s", name, err, decision), ElapsedSec: sessionElapsed()})
+			continue
+		}
+		created(pod)
+		emit(Event{Type: evCreated, PodID: pod.ID, GPU: pod.Machine.GpuDisplayName, DataCenter: pod.Machine.DataCenterID, CostPerHr: pod.CostPerHr})
+
+		_, ports, err := waitForPodReady(apiKey, pod.ID, podURL(pod.ID, desktopPort), plan.current().Spot, limits.forPlan(plan))
+		if err == nil {
+			return pod, ports, nil
+		}
+
+		var (
+			stuck       *stuckError
+			interrupted *interruptedError
+			decision    string
+			retry       bool
+		)
+		switch {
+		case limits.expired():
+		case errors.As(err, &stuck):
+			if decision, retry = plan.next(stuck, pod.Machine.GpuDisplayName, pod.Machine.DataCenterID); retry {
+				emit(Event{Type: evRetry, PodID: pod.ID, Message: fmt.Sprintf("Pod %s %v. %s", pod.ID, stuck, decision), ElapsedSec: sessionElapsed()})
+			}
+		case errors.As(err, &interrupted):
+			if decision, retry = plan.interrupted(pod.Machine.GpuDisplayName, pod.Machine.DataCenterID); retry {
+				emit(Event{Type: evRetry, PodID: pod.ID, Message: fmt.Sprintf("%s. %s", capitalize(interrupted.Error()), decision), ElapsedSec: sessionElapsed()})
+			}
+		}
+
+		// Don't leave a half-started pod billing
+		if derr := deletePod(apiKey, pod.ID); derr != nil {
+			reportTerminationFailure(apiKey, pod.ID, derr)
+			return nil, nil, fmt.Errorf("%v (and terminating pod %s failed: %v)", err, pod.ID, derr)
+		}
+		if !retry {
+			return nil, nil, err
+		}
+	}
+}
+
+// matchesGPU compares a GPU type ID with a display name ("NVIDIA RTX A5000"
+// is shown as "RTX A5000")
+func matchesGPU(typeID, gpu string) bool {
+	return gpu != "" && strings.HasSuffix(strings.ToLower(typeID), strings.ToLower(gpu))
+}
+
+func containsGPU(types []string, gpu string) bool {
+	for _, t := range types {
+		if matchesGPU(t, gpu) {
+			return true
+		}
+	}
+	return false
+}
+
+// withoutGPU drops the GPU type a pod got
+func withoutGPU(types []string, gpu string) []string {
+	var rest []string
+	for _, t := range types {
+		if !matchesGPU(t, gpu) {
+			rest = append(rest, t)
+		}
+	}
+	return rest
+}
+
+// without drops item, or the first entry when item isn't in the list
+func without(list []string, item string) []string {
+	var rest []string
+	for _, v := range list {
+		if v != item {
+			rest = append(rest, v)
+		}
+	}
+	if len(rest) == len(list) && len(list) > 0 {
+		rest = list[1:]
+	}
+	return rest
+}
@@ -0,0 +1,780 @@
+// Copyright (c) 2025-2026 Mik Gangal
+// Licensed under CC BY-NC-SA 4.0 - https://creativecommons.org/licenses/by-nc-sa/4.0/
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Scheduled launches: bookings (one-off or cron-like) are kept in
+// schedule.json in the state directory. "schedule run" is a long-lived
+// daemon that starts each booking's pod ahead of time so the desktop is
+// ready at the booked minute, and terminates it at the booked end. The
+// pods also end themselves at that time, so a stopped daemon can't leave
+// them billing.
+
+const (
+	scheduleFileName     = "schedule.json"
+	scheduleLockName     = "schedule.lock"
+	scheduleTick         = 30 * time.Second
+	scheduleLockStale    = 2 * time.Minute
+	scheduleKeepRuns     = 7 * 24 * time.Hour
+	defaultScheduleLead  = 10 * time.Minute
+	defaultScheduleHours = 2
+)
+
+// Run statuses
+const (
+	runLaunching = "launching"
+	runReady     = "ready"
+	runFailed    = "failed"
+	runDone      = "done"
+)
+
+// ScheduleEntry is one booking, either at a fixed time or repeating
+type ScheduleEntry struct {
+	Name    string    `json:"name"`
+	Profile string    `json:"profile"`
+	At      time.Time `json:"at,omitempty"`
+	Cron    string    `json:"cron,omitempty"`
+	Hours   float64   `json:"hours"`
+	LeadMin int       `json:"leadMin"`
+	Created time.Time `json:"created"`
+}
+
+func (e *ScheduleEntry) duration() time.Duration {
+	return time.Duration(e.Hours * float64(time.Hour))
+}
+
+func (e *ScheduleEntry) lead() time.Duration {
+	return time.Duration(e.LeadMin) * time.Minute
+}
+
+// next returns the first booked start whose end is still after t, or the
+// zero time if there is none
+func (e *ScheduleEntry) next(t time.Time) time.Time {
+	if e.Cron == "" {
+		if e.At.Add(e.duration()).After(t) {
+			return e.At
+		}
+		return time.Time{}
+	}
+	spec, err := parseCron(e.Cron)
+	if err != nil {
+		return time.Time{}
+	}
+	return spec.next(t.Add(-e.duration()))
+}
+
+func (e *ScheduleEntry) when() string {
+	if e.Cron != "" {
+		return "cron " + e.Cron
+	}
+	return e.At.Format("2006-01-02 15:04")
+}
+
+// ScheduleRun is one occurrence of a booking and the pod started for it
+type ScheduleRun struct {
+	Entry   string    `json:"entry"`
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	PodID   string    `json:"podId,omitempty"`
+	Account string    `json:"account,omitempty"` // saved account the pod runs on ("" = the daemon's)
+	Status  string    `json:"status"`
+	Error   string    `json:"error,omitempty"`
+}
+
+func (r *ScheduleRun) active() bool {
+	return r.Status == runLaunching || r.Status == runReady
+}
+
+// Schedule is what schedule.json holds
+type Schedule struct {
+	Entries []ScheduleEntry `json:"entries"`
+	Runs    []ScheduleRun   `json:"runs"`
+}
+
+func (s *Schedule) entry(name string) *ScheduleEntry {
+	for i := range s.Entries {
+		if s.Entries[i].Name == name {
+			return &s.Entries[i]
+		}
+	}
+	return nil
+}
+
+func (s *Schedule) run(entry string, start time.Time) *ScheduleRun {
+	for i := range s.Runs {
+		if s.Runs[i].Entry == entry && s.Runs[i].Start.Equal(start) {
+			return &s.Runs[i]
+		}
+	}
+	return nil
+}
+
+var scheduleMu sync.Mutex
+
+func schedulePath() (string, error) {
+	dir, err := getStateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, scheduleFileName), nil
+}
+
+func loadSchedule() (*Schedule, error) {
+	path, err := schedulePath()
+	if err != nil {
+		return nil, err
+	}
+	s := &Schedule{}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read schedule: %w", err)
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("could not parse schedule: %w", err)
+	}
+	return s, nil
+}
+
+func saveSchedule(s *Schedule) error {
+	path, err := schedulePath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not encode schedule: %w", err)
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("could not write schedule: %w", err)
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// updateSchedule loads the schedule, applies fn and saves the result. The
+// CLI and the daemon may both change it, so it is always re-read.
+func updateSchedule(fn func(s *Schedule)) error {
+	scheduleMu.Lock()
+	defer scheduleMu.Unlock()
+
+	s, err := loadSchedule()
+	if err != nil {
+		return err
+	}
+	fn(s)
+	return saveSchedule(s)
+}
+
+// cronSpec is a parsed five-field cron expression (minute hour day-of-month
+// month day-of-week) supporting *, lists, ranges and steps
+type cronSpec struct {
+	minute, hour, dom, month, dow map[int]bool
+	domAny, dowAny                bool
+}
+
+func parseCron(expr string) (*cronSpec, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron needs 5 fields (minute hour day month weekday), got %d", len(fields))
+	}
+	bounds := [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	var sets [5]map[int]bool
+	for i, f := range fields {
+		set, err := parseCronField(f, bounds[i][0], bounds[i][1])
+		if err != nil {
+			return nil, fmt.Errorf("cron field %q: %w", f, err)
+		}
+		sets[i] = set
+	}
+	// Sunday is both 0 and 7
+	if sets[4][7] {
+		sets[4][0] = true
+	}
+	return &cronSpec{
+		minute: sets[0], hour: sets[1], dom: sets[2], month: sets[3], dow: sets[4],
+		domAny: strings.HasPrefix(fields[2], "*"), dowAny: strings.HasPrefix(fields[4], "*"),
+	}, nil
+}
+
+func parseCronField(field string, lo, hi int) (map[int]bool, error) {
+	set := make(map[int]bool)
+	for _, part := range strings.Split(field, ",") {
+		rng, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("bad step")
+			}
+			rng, step = part[:i], n
+		}
+		from, to := lo, hi
+		if rng != "*" {
+			a, b, isRange := strings.Cut(rng, "-")
+			var err error
+			if from, err = strconv.Atoi(a); err != nil {
+				return nil, fmt.Errorf("bad number %q", a)
+			}
+			to = from
+			if isRange {
+				if to, err = strconv.Atoi(b); err != nil {
+					return nil, fmt.Errorf("bad number %q", b)
+				}
+			} else if step > 1 {
+				to = hi
+			}
+		}
+		if from < lo || to > hi || from > to {
+			return nil, fmt.Errorf("out of range %d-%d", lo, hi)
+		}
+		for v := from; v <= to; v += step {
+			set[v] = true
+		}
+	}
+	return set, nil
+}
+
+func (c *cronSpec) matches(t time.Time) bool {
+	if !c.minute[t.Minute()] || !c.hour[t.Hour()] || !c.month[int(t.Month())] {
+		return false
+	}
+	// Like cron: if both day fields are restricted, either one may match
+	dom, dow := c.dom[t.Day()], c.dow[int(t.Weekday())]
+	switch {
+	case c.domAny && c.dowAny:
+		return true
+	case c.domAny:
+		return dow
+	case c.dowAny:
+		return dom
+	}
+	return dom || dow
+}
+
+// next returns the first matching minute after t, or the zero time if
+// nothing matches within a year
+func (c *cronSpec) next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	for limit := t.AddDate(1, 0, 1); t.Before(limit); t = t.Add(time.Minute) {
+		if c.matches(t) {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func runScheduleCommand(args []string) int {
+	if len(args) == 0 {
+		fmt.Println("usage: slicer-launcher schedule add -name NAME (-at \"2006-01-02 15:04\" | -cron \"0 9 * * 1-5\") [-hours 2] [-profile P] [-lead 10m]")
+		fmt.Println("       slicer-launcher schedule list | remove NAME | run")
+		return exitUsage
+	}
+	switch args[0] {
+	case "add":
+		return runScheduleAdd(args[1:])
+	case "list":
+		return runScheduleList()
+	case "remove":
+		return runScheduleRemove(args[1:])
+	case "run":
+		return runScheduleDaemon()
+	}
+	fmt.Printf("unknown schedule command %q\n", args[0])
+	return exitUsage
+}
+
+func runScheduleAdd(args []string) int {
+	fs := flag.NewFlagSet("schedule add", flag.ContinueOnError)
+	name := fs.String("name", "", "booking name, also used as the pod name")
+	at := fs.String("at", "", "one-off start (2006-01-02 15:04, or 15:04 today)")
+	cron := fs.String("cron", "", "repeating start as a cron expression (minute hour day month weekday)")
+	hours := fs.Float64("hours", defaultScheduleHours, "how long the desktop is booked for")
+	profileName := fs.String("profile", defaultProfileName, "settings profile the pod is created from")
+	lead := fs.Duration("lead", defaultScheduleLead, "start the pod this long before the booked time")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	switch {
+	case !fleetNamePattern.MatchString(*name):
+		fmt.Println("-name must be up to 32 lowercase letters, digits and dashes")
+		return exitUsage
+	case (*at == "") == (*cron == ""):
+		fmt.Println("give either -at or -cron")
+		return exitUsage
+	case *hours <= 0 || *lead < 0:
+		fmt.Println("-hours must be positive and -lead not negative")
+		return exitUsage
+	}
+
+	entry := ScheduleEntry{Name: *name, Hours: *hours, LeadMin: int(lead.Minutes()), Created: time.Now()}
+	if *at != "" {
+		t, err := parseEndTime(*at, time.Now())
+		if err != nil {
+			fmt.Printf("-at: %v\n", err)
+			return exitUsage
+		}
+		entry.At = t
+	} else {
+		if _, err := parseCron(*cron); err != nil {
+			fmt.Printf("-cron: %v\n", err)
+			return exitUsage
+		}
+		entry.Cron = *cron
+	}
+
+	settings, err := loadSettings()
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitUsage
+	}
+	profile, err := settings.profile(*profileName)
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitUsage
+	}
+	entry.Profile = profile.Name
+
+	first := entry.next(time.Now())
+	if first.IsZero() {
+		fmt.Printf("%sError: %s never comes up%s\n", colorRed, entry.when(), colorReset)
+		return exitUsage
+	}
+
+	exists := false
+	err = updateSchedule(func(s *Schedule) {
+		if exists = s.entry(entry.Name) != nil; !exists {
+			s.Entries = append(s.Entries, entry)
+		}
+	})
+	switch {
+	case err != nil:
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitError
+	case exists:
+		fmt.Printf("A booking named %q already exists. Remove it first or pick another -name.\n", entry.Name)
+		return exitUsage
+	}
+
+	fmt.Printf("✓ Booked %q (%s, %s, profile %s)\n", entry.Name, entry.when(), formatDuration(entry.duration()), entry.Profile)
+	fmt.Printf("  Next: %s, pod starts at %s\n", first.Format("Mon 2006-01-02 15:04"), first.Add(-entry.lead()).Format("15:04"))
+	if !scheduleDaemonRunning() {
+		fmt.Printf("%sThe schedule daemon is not running - start it with 'slicer-launcher schedule run'%s\n", colorYellow, colorReset)
+	}
+	return exitOK
+}
+
+func runScheduleList() int {
+	s, err := loadSchedule()
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitError
+	}
+	if len(s.Entries) == 0 && len(s.Runs) == 0 {
+		fmt.Println("Nothing is booked. Add a booking with 'slicer-launcher schedule add'.")
+		return exitOK
+	}
+
+	now := time.Now()
+	fmt.Printf("%s%-20s %-24s %-8s %-12s %s%s\n", colorDim, "Name", "When", "Hours", "Profile", "Next", colorReset)
+	for _, e := range s.Entries {
+		next := "-"
+		if t := e.next(now); !t.IsZero() {
+			next = t.Format("Mon 01-02 15:04")
+		}
+		fmt.Printf("%-20s %-24s %-8g %-12s %s\n", e.Name, truncate(e.when(), 24), e.Hours, e.Profile, next)
+	}
+
+	if len(s.Runs) > 0 {
+		fmt.Printf("\n%s%-20s %-16s %-6s %-16s %s%s\n", colorDim, "Booking", "Start", "End", "Pod", "Status", colorReset)
+		for _, r := range s.Runs {
+			status := r.Status
+			if r.Error != "" {
+				status += ": " + r.Error
+			}
+			fmt.Printf("%-20s %-16s %-6s %-16s %s\n", r.Entry, r.Start.Format("2006-01-02 15:04"), r.End.Format("15:04"), r.PodID, status)
+		}
+	}
+
+	if !scheduleDaemonRunning() {
+		fmt.Printf("\n%sThe schedule daemon is not running - start it with 'slicer-launcher schedule run'%s\n", colorYellow, colorReset)
+	}
+	return exitOK
+}
+
+func runScheduleRemove(args []string) int {
+	if len(args) != 1 {
+		fmt.Println("usage: slicer-launcher schedule remove NAME")
+		return exitUsage
+	}
+	name := args[0]
+
+	found := false
+	var live []ScheduleRun
+	err := updateSchedule(func(s *Schedule) {
+		for i, e := range s.Entries {
+			if e.Name == name {
+				s.Entries = append(s.Entries[:i], s.Entries[i+1:]...)
+				found = true
+				break
+			}
+		}
+		for _, r := range s.Runs {
+			if r.Entry == name && r.active() && r.PodID != "" {
+				live = append(live, r)
+			}
+		}
+	})
+	switch {
+	case err != nil:
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitError
+	case !found:
+		fmt.Printf("No booking named %q\n", name)
+		return exitUsage
+	}
+
+	fmt.Printf("✓ Removed %q\n", name)
+	// The daemon still ends pods that are already running at their booked end
+	for _, r := range live {
+		fmt.Printf("  Pod %s keeps running until %s - end it now with 'slicer-launcher terminate %s'\n", r.PodID, r.End.Format("15:04"), r.PodID)
+	}
+	return exitOK
+}
+
+// scheduleLockPath returns the daemon's lock file. The daemon touches it
+// every tick; a lock that hasn't been touched for a while is left over from
+// a daemon that died.
+func scheduleLockPath() (string, error) {
+	dir, err := getStateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, scheduleLockName), nil
+}
+
+func scheduleDaemonRunning() bool {
+	path, err := scheduleLockPath()
+	if err != nil {
+		return false
+	}
+	info, err := os.Stat(path)
+	return err == nil && time.Since(info.ModTime()) < scheduleLockStale
+}
+
+func touchScheduleLock() {
+	path, err := scheduleLockPath()
+	if err != nil {
+		return
+	}
+	if err := os.WriteFile(path, []byte(strconv.Itoa(os.Getpid())+"\n"), 0600); err != nil {
+		logEvent(levelWarn, "Could not update the schedule lock: %v", err)
+	}
+}
+
+// scheduleDaemon starts and ends the booked pods
+type scheduleDaemon struct {
+	apiKey string
+
+	mu       sync.Mutex
+	inFlight map[string]bool // runs being launched, by runKey
+}
+
+// key returns the API key of a run's account
+func (d *scheduleDaemon) key(account string) (string, error) {
+	return accountAPIKey(account, d.apiKey)
+}
+
+func runKey(entry string, start time.Time) string {
+	return entry + "@" + start.Format(time.RFC3339)
+}
+
+func runScheduleDaemon() int {
+	if scheduleDaemonRunning() {
+		fmt.Println("The schedule daemon is already running.")
+		return exitUsage
+	}
+	apiKey, err := commandSetup()
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitUsage
+	}
+
+	touchScheduleLock()
+	atExit(func() {
+		if path, err := scheduleLockPath(); err == nil {
+			os.Remove(path)
+		}
+	})
+	closeLog, err := initLogging(false)
+	if err != nil {
+		logEvent(levelWarn, "Session log disabled: %v", err)
+	}
+	atExit(closeLog)
+	subscribe((&slogSink{}).handle)
+	subscribe(scheduleEventPrinter)
+	subscribe((&desktopNotifier{settings: appSettings}).handle)
+	if len(appSettings.Webhooks) > 0 {
+		hooks := newWebhookSink(appSettings, "schedule")
+		subscribe(hooks.handle)
+		atExit(func() { hooks.queue.flush(webhookFlushWait) })
+	}
+
+	d := &scheduleDaemon{apiKey: apiKey, inFlight: make(map[string]bool)}
+	d.recover()
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	scheduleLog("Schedule daemon started - Ctrl-C to stop")
+	ticker := time.NewTicker(scheduleTick)
+	defer ticker.Stop()
+	for {
+		touchScheduleLock()
+		d.tick(time.Now())
+		select {
+		case <-ticker.C:
+		case <-sig:
+			scheduleLog("Schedule daemon stopped. Running pods still end themselves at their booked end.")
+			return exitOK
+		}
+	}
+}
+
+// recover deals with launches a previous daemon didn't finish: their pods
+// may be half-started, so they are terminated and launched again
+func (d *scheduleDaemon) recover() {
+	var stale []ScheduleRun
+	err := updateSchedule(func(s *Schedule) {
+		kept := s.Runs[:0]
+		for _, r := range s.Runs {
+			if r.Status == runLaunching {
+				if r.PodID != "" {
+					stale = append(stale, r)
+				}
+				continue
+			}
+			kept = append(kept, r)
+		}
+		s.Runs = kept
+	})
+	if err != nil {
+		logEvent(levelError, "Could not read the schedule: %v", err)
+		return
+	}
+	for _, r := range stale {
+		scheduleLog("Pod %s was still starting when the daemon stopped - terminating it and starting over", r.PodID)
+		apiKey, err := d.key(r.Account)
+		if err == nil {
+			err = deletePod(apiKey, r.PodID)
+		}
+		if err != nil {
+			reportTerminationFailure(apiKey, r.PodID, err)
+		}
+	}
+}
+
+// tick starts the bookings that are due, ends the ones that are over and
+// drops what is finished
+func (d *scheduleDaemon) tick(now time.Time) {
+	var due []ScheduleRun
+	var dueEntries []ScheduleEntry
+	var over []ScheduleRun
+	err := updateSchedule(func(s *Schedule) {
+		for _, e := range s.Entries {
+			start := e.next(now)
+			if start.IsZero() || now.Before(start.Add(-e.lead())) || s.run(e.Name, start) != nil {
+				continue
+			}
+			run := ScheduleRun{Entry: e.Name, Start: start, End: start.Add(e.duration()), Status: runLaunching}
+			s.Runs = append(s.Runs, run)
+			due = append(due, run)
+			dueEntries = append(dueEntries, e)
+		}
+
+		kept := s.Runs[:0]
+		for _, r := range s.Runs {
+			if r.Status == runReady && !now.Before(r.End) {
+				over = append(over, r)
+			}
+			// Keep finished runs for a while so 'schedule list' shows them
+			if !r.active() && now.Sub(r.End) > scheduleKeepRuns {
+				continue
+			}
+			kept = append(kept, r)
+		}
+		s.Runs = kept
+
+		// One-off bookings are done once their time is over
+		entries := s.Entries[:0]
+		for _, e := range s.Entries {
+			if e.Cron == "" && !e.At.Add(e.duration()).After(now) {
+				continue
+			}
+			entries = append(entries, e)
+		}
+		s.Entries = entries
+	})
+	if err != nil {
+		logEvent(levelError, "Could not update the schedule: %v", err)
+		return
+	}
+
+	for i, run := range due {
+		go d.launch(dueEntries[i], run)
+	}
+	for _, run := range over {
+		d.end(run)
+	}
+}
+
+func (d *scheduleDaemon) setRun(run ScheduleRun, fn func(r *ScheduleRun)) {
+	err := updateSchedule(func(s *Schedule) {
+		if r := s.run(run.Entry, run.Start); r != nil {
+			fn(r)
+		}
+	})
+	if err != nil {
+		logEvent(levelWarn, "Could not save the schedule: %v", err)
+	}
+}
+
+// launch starts the pod for one booking and waits for its desktop. A pod
+// that isn't ready by the booked end is given up on.
+func (d *scheduleDaemon) launch(entry ScheduleEntry, run ScheduleRun) {
+	key := runKey(run.Entry, run.Start)
+	d.mu.Lock()
+	if d.inFlight[key] {
+		d.mu.Unlock()
+		return
+	}
+	d.inFlight[key] = true
+	d.mu.Unlock()
+	defer func() {
+		d.mu.Lock()
+		delete(d.inFlight, key)
+		d.mu.Unlock()
+	}()
+
+	fail := func(err error) {
+		d.setRun(run, func(r *ScheduleRun) { r.Status, r.Error = runFailed, err.Error() })
+		logEvent(levelError, "%s: %v", run.Entry, err)
+	}
+
+	scheduleLog("Starting %s for %s (booked %s-%s)", entry.Name, entry.Profile, run.Start.Format("15:04"), run.End.Format("15:04"))
+	profile, err := appSettings.profile(entry.Profile)
+	if err != nil {
+		fail(err)
+		return
+	}
+	account, apiKey, err := profileAPIKey(profile, d.apiKey)
+	if err != nil {
+		fail(err)
+		return
+	}
+	d.setRun(run, func(r *ScheduleRun) { r.Account = account })
+	if err := prepareVolume(apiKey, &profile); err != nil {
+		fail(err)
+		return
+	}
+	if _, err := checkBalance(apiKey, profile, 1); err != nil {
+		fail(fmt.Errorf("account %s: %w", accountLabel(account), err))
+		return
+	}
+
+	deadline := time.Now().Add(defaultLaunchTimeout)
+	if run.End.Before(deadline) {
+		deadline = run.End
+	}
+	limits := launchLimits{queue: defaultQueueTimeout, pull: defaultPullTimeout, deadline: deadline}
+	pod, _, err := launchReady(apiKey, entry.Name, profile, limits,
+		func() (map[string]string, error) { return deadlineEnv(run.End) },
+		func(pod *PodResponse) {
+			d.setRun(run, func(r *ScheduleRun) { r.PodID = pod.ID })
+		})
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	d.setRun(run, func(r *ScheduleRun) { r.Status = runReady })
+	emit(Event{
+		Type:       evReady,
+		PodID:      pod.ID,
+		GPU:        pod.Machine.GpuDisplayName,
+		CostPerHr:  pod.CostPerHr,
+		DesktopURL: podURL(pod.ID, desktopPort),
+		FilesURL:   podURL(pod.ID, fileBrowserPort) + "/FILE%20TRANSFERS/",
+	})
+}
+
+// end terminates a booking's pod at the booked end
+func (d *scheduleDaemon) end(run ScheduleRun) {
+	emit(Event{Type: evTerminating, PodID: run.PodID})
+	apiKey, err := d.key(run.Account)
+	if err == nil {
+		err = deletePod(apiKey, run.PodID)
+	}
+	if err != nil {
+		// The pod still ends itself; say so but try again next tick
+		reportTerminationFailure(apiKey, run.PodID, err)
+		return
+	}
+	d.setRun(run, func(r *ScheduleRun) { r.Status = runDone })
+	emit(Event{Type: evTerminated, PodID: run.PodID})
+}
+
+// scheduleLog prints a timestamped line; the daemon's output is read later
+func scheduleLog(format string, a ...interface{}) {
+	fmt.Printf("%s[%s]%s %s\n", colorDim, time.Now().Format("2006-01-02 15:04:05"), colorReset, fmt.Sprintf(format, a...))
+}
+
+func scheduleEventPrinter(ev Event) {
+	switch ev.Type {
+	case evCreated:
+		scheduleLog("%s✓%s Pod %s (%s, $%.2f/hr)", colorGreen, colorReset, ev.PodID, ev.GPU, ev.CostPerHr)
+	case evRetry:
+		scheduleLog("%s↻ %s%s", colorYellow, ev.Message, colorReset)
+	case evReady:
+		scheduleLog("%s✓ Desktop ready%s %s", colorGreen, colorReset, ev.DesktopURL)
+	case evTerminated:
+		scheduleLog("%s✓%s Pod %s terminated", colorGreen, colorReset, ev.PodID)
+	case evLog:
+		switch ev.Level {
+		case levelWarn:
+			scheduleLog("%sWarning: %s%s", colorYellow, ev.Message, colorReset)
+		case levelError:
+			scheduleLog("%sError: %s%s", colorRed, ev.Message, colorReset)
+		}
+	}
+}
+
+// scheduledPods maps the pods started for bookings to their booking names
+func scheduledPods() map[string]string {
+	pods := make(map[string]string)
+	s, err := loadSchedule()
+	if err != nil {
+		return pods
+	}
+	for _, r := range s.Runs {
+		if r.PodID != "" {
+			pods[r.PodID] = r.Entry
+		}
+	}
+	return pods
+}
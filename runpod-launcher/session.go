@@ -0,0 +1,368 @@
+// Copyright (c) 2025-2026 Mik Gangal
+// Licensed under CC BY-NC-SA 4.0 - https://creativecommons.org/licenses/by-nc-sa/4.0/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	balanceInterval = 5 * time.Minute
+	budgetCheck     = 30 * time.Second
+	budgetExtension = time.Hour
+	budgetWarnAt    = 0.8
+
+	idleCheck       = time.Minute
+	idleUtilPercent = 5.0 // below this the GPU counts as idle
+
+	desktopPort = 6080 // noVNC
+)
+
+// session is a running pod plus the actions the front ends can trigger on it
+type session struct {
+	apiKey     string
+	podID      string
+	gpuName    string
+	desktopURL string
+	filesURL   string
+	tcpPorts   map[int]PortInfo
+	guard      *podGuard
+	fb         *fileBrowserClient
+
+	mu          sync.Mutex
+	stopped     bool
+	interrupted bool // RunPod took the spot pod back (see spot.go)
+	exporting   bool // a remote export is running (see export.go)
+
+	// expired is closed when the session budget (max lifetime) runs out
+	expired     chan struct{}
+	expiredOnce sync.Once
+
+	// quit is closed when a front end other than the active one (e.g. the
+	// web dashboard) asks to end the session and terminate the pod
+	quit     chan struct{}
+	quitOnce sync.Once
+}
+
+func newSession(apiKey, podID, gpuName string, guard *podGuard) *session {
+	return &session{
+		apiKey:     apiKey,
+		podID:      podID,
+		gpuName:    gpuName,
+		desktopURL: podURL(podID, desktopPort),
+		filesURL:   podURL(podID, fileBrowserPort) + "/FILE%20TRANSFERS/",
+		guard:      guard,
+		fb:         newFileBrowserClient(podID),
+		expired:    make(chan struct{}),
+		quit:       make(chan struct{}),
+	}
+}
+
+// podURL is the address of a pod port behind RunPod's HTTPS proxy
+func podURL(podID string, port int) string {
+	return fmt.Sprintf("https://%s-%d.proxy.runpod.net", podID, port)
+}
+
+func (s *session) openDesktop() {
+	if err := openBrowser(s.desktopURL); err != nil {
+		logEvent(levelWarn, "Could not open browser. Open this URL: %s", s.desktopURL)
+	}
+}
+
+func (s *session) openFiles() {
+	if err := openBrowser(s.filesURL); err != nil {
+		logEvent(levelWarn, "Could not open browser. Open this URL: %s", s.filesURL)
+	}
+}
+
+// sshCommand returns the command line for SSH into the pod, or "" if no SSH port is mapped
+func (s *session) sshCommand() string {
+	port, ok := s.tcpPorts[22]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("ssh root@%s -p %d", port.IP, port.PublicPort)
+}
+
+func (s *session) copySSH() {
+	cmd := s.sshCommand()
+	if cmd == "" {
+		logEvent(levelWarn, "No SSH port is exposed on this pod")
+		return
+	}
+	if err := copyToClipboard(cmd); err != nil {
+		logEvent(levelWarn, "Could not copy to clipboard (%v): %s", err, cmd)
+		return
+	}
+	logEvent(levelOK, "Copied to clipboard: %s (password: runpod)", cmd)
+}
+
+func (s *session) isStopped() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stopped
+}
+
+// stop stops the pod without terminating it (GPU billing ends, the pod is kept)
+func (s *session) stop() error {
+	if err := stopPod(s.apiKey, s.podID); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.stopped = true
+	s.mu.Unlock()
+
+	if err := setPodStatus(s.podID, podStatusStopped); err != nil {
+		logEvent(levelWarn, "Could not update launcher state: %v", err)
+	}
+	emit(Event{Type: evStopped, PodID: s.podID})
+	return nil
+}
+
+// extendBudget moves the session deadline and tells the pod right away
+func (s *session) extendBudget(d time.Duration) {
+	deadline := s.guard.extend(d)
+	if err := s.guard.heartbeat(s.fb); err != nil {
+		logEvent(levelWarn, "Could not send new deadline to pod: %v", err)
+	}
+	emit(Event{Type: evBudget, Deadline: &deadline})
+}
+
+// watchBudget warns when 80% of the session budget is used and closes
+// s.expired once the deadline passes
+func (s *session) watchBudget(stop <-chan struct{}) {
+	go func() {
+		ticker := time.NewTicker(budgetCheck)
+		defer ticker.Stop()
+		warned := false
+		for {
+			select {
+			case <-ticker.C:
+				d := s.guard.currentDeadline()
+				if d.IsZero() {
+					continue
+				}
+				if time.Now().After(d) {
+					emit(Event{Type: evBudgetThreshold, Percent: 100, Level: levelWarn, Deadline: &d,
+						Message: fmt.Sprintf("Session budget reached (%s) - terminating the pod", d.Format("15:04"))})
+					s.expiredOnce.Do(func() { close(s.expired) })
+					return
+				}
+
+				// An extension can bring usage back under 80%, so warn again later
+				used := time.Since(launchStart).Seconds() / d.Sub(launchStart).Seconds()
+				if used < budgetWarnAt {
+					warned = false
+				} else if !warned {
+					warned = true
+					emit(Event{Type: evBudgetThreshold, Percent: 80, Level: levelWarn, Deadline: &d,
+						Message: fmt.Sprintf("80%% of the session budget used - the pod shuts down at %s", d.Format("15:04"))})
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// watchIdle warns once the GPU has been idle for the given time. The
+// warning repeats after the GPU was busy again.
+func (s *session) watchIdle(after time.Duration, stop <-chan struct{}) {
+	if after <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(idleCheck)
+		defer ticker.Stop()
+		var idleSince time.Time
+		warned := false
+		for {
+			select {
+			case <-ticker.C:
+				if s.isStopped() {
+					continue
+				}
+				util, err := getGPUUtilization(s.apiKey, s.podID)
+				if err != nil {
+					continue
+				}
+				if util >= idleUtilPercent {
+					idleSince, warned = time.Time{}, false
+					continue
+				}
+				if idleSince.IsZero() {
+					idleSince = time.Now()
+				}
+				if idle := time.Since(idleSince); !warned && idle >= after {
+					warned = true
+					emit(Event{Type: evIdle, Level: levelWarn,
+						Message: fmt.Sprintf("GPU idle for %s - terminate the pod if you are done", formatDuration(idle))})
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// getGPUUtilization returns the highest GPU utilization of a pod in percent
+func getGPUUtilization(apiKey, podID string) (float64, error) {
+	query := fmt.Sprintf(`{"query": "query { pod(input: {podId: \"%s\"}) { runtime { gpus { id gpuUtilPercent } } } }"}`, podID)
+	req, err := http.NewRequest("POST", runpodGraphQLURL+"?api_key="+apiKey, strings.NewReader(query))
+	if err != nil {
+		return 0, fmt.Errorf("could not create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := newHTTPClient(10 * time.Second).Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Data struct {
+			Pod struct {
+				Runtime *struct {
+					Gpus []struct {
+						GpuUtilPercent float64 `json:"gpuUtilPercent"`
+					} `json:"gpus"`
+				} `json:"runtime"`
+			} `json:"pod"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, fmt.Errorf("could not parse response: %w", err)
+	}
+	if result.Data.Pod.Runtime == nil || len(result.Data.Pod.Runtime.Gpus) == 0 {
+		return 0, fmt.Errorf("no GPU metrics for pod %s", podID)
+	}
+
+	var util float64
+	for _, gpu := range result.Data.Pod.Runtime.Gpus {
+		util = max(util, gpu.GpuUtilPercent)
+	}
+	return util, nil
+}
+
+// trackBalance emits the account balance now and every few minutes
+func (s *session) trackBalance(stop <-chan struct{}) {
+	report := func() {
+		if info, err := getAccountInfo(s.apiKey); err == nil {
+			emit(Event{Type: evBalance, Balance: info.Balance, CostPerHr: info.CostPerHr, ElapsedSec: sessionElapsed()})
+		}
+	}
+	report()
+
+	go func() {
+		ticker := time.NewTicker(balanceInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				report()
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// requestQuit ends the session; the pod is terminated by the launch flow
+func (s *session) requestQuit() {
+	s.quitOnce.Do(func() { close(s.quit) })
+}
+
+// transferProgressInterval limits how often transfer progress is emitted
+const transferProgressInterval = 500 * time.Millisecond
+
+// progressReader emits transfer events while data flows through it
+type progressReader struct {
+	r        io.Reader
+	transfer Transfer
+	start    time.Time
+	last     time.Time
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.transfer.Bytes += int64(n)
+	if time.Since(p.last) >= transferProgressInterval {
+		p.last = time.Now()
+		t := p.transfer
+		t.Seconds = time.Since(p.start).Seconds()
+		emit(Event{Type: evTransfer, Transfer: &t})
+	}
+	return n, err
+}
+
+// finish emits the final event of a transfer
+func (p *progressReader) finish(err error) {
+	t := p.transfer
+	t.Seconds = time.Since(p.start).Seconds()
+	t.Done = err == nil
+	if err != nil {
+		t.Error = err.Error()
+	}
+	emit(Event{Type: evTransfer, Transfer: &t})
+}
+
+// upload copies a local stream into the pod's transfer folder. name may
+// contain subfolders (e.g. a DICOM series uploaded as a folder).
+func (s *session) upload(name string, r io.Reader, size int64) error {
+	clean := path.Clean("/" + strings.ReplaceAll(name, "\\", "/"))
+	if clean == "/" {
+		return fmt.Errorf("invalid file name %q", name)
+	}
+	pr := &progressReader{r: r, transfer: Transfer{Name: strings.TrimPrefix(clean, "/"), Direction: "upload", Total: size}, start: time.Now()}
+	err := s.fb.upload(transferDir+clean, pr, size)
+	pr.finish(err)
+	return err
+}
+
+// listExports returns the Export_* folders Slicer wrote, newest first
+func (s *session) listExports() ([]fileBrowserItem, error) {
+	items, err := s.fb.list(transferDir)
+	if err != nil {
+		return nil, err
+	}
+	var exports []fileBrowserItem
+	for _, item := range items {
+		if item.IsDir && strings.HasPrefix(item.Name, "Export_") {
+			exports = append(exports, item)
+		}
+	}
+	sort.Slice(exports, func(i, j int) bool { return exports[i].Name > exports[j].Name })
+	return exports, nil
+}
+
+// downloadExport writes an Export_* folder as a zip archive to w
+func (s *session) downloadExport(name string, w io.Writer) error {
+	if name != path.Base(name) || !strings.HasPrefix(name, "Export_") {
+		return fmt.Errorf("invalid export name %q", name)
+	}
+	body, size, err := s.fb.download(transferDir + "/" + name)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	total := size
+	if total < 0 {
+		total = 0
+	}
+	pr := &progressReader{r: body, transfer: Transfer{Name: name + ".zip", Direction: "download", Total: total}, start: time.Now()}
+	_, err = io.Copy(w, pr)
+	pr.finish(err)
+	return err
+}
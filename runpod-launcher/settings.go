@@ -0,0 +1,199 @@
+// Copyright (c) 2025-2026 Mik Gangal
+// Licensed under CC BY-NC-SA 4.0 - https://creativecommons.org/licenses/by-nc-sa/4.0/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+)
+
+// settings.json in the state directory holds user preferences. It is created
+// with the defaults on first run so there is something to edit.
+
+const settingsFileName = "settings.json"
+
+// Desktop notifications that can be switched off in settings.json
+const (
+	notifyReady            = "ready"
+	notifyFileBrowserReady = "fileBrowserReady"
+	notifyBudget80         = "budget80"
+	notifyBudget100        = "budget100"
+	notifyIdle             = "idle"
+	notifyTerminated       = "terminated"
+	notifyTerminateFailed  = "terminateFailed"
+	notifyExportDownloaded = "exportDownloaded"
+	notifyInterrupted      = "interrupted"
+)
+
+// Settings are the user preferences read from settings.json
+type Settings struct {
+	// Notifications enables desktop notifications per event; missing entries are on
+	Notifications map[string]bool `json:"notifications"`
+
+	// IdleMinutes warns when the GPU has been idle this long (0 = off)
+	IdleMinutes int `json:"idleMinutes"`
+
+	// User names the person launching in webhooks (default: login name)
+	User string `json:"user,omitempty"`
+
+	// Profiles are named launch configurations selected with --profile
+	Profiles map[string]Profile `json:"profiles,omitempty"`
+
+	// Webhooks receive session events (see webhook.go)
+	Webhooks []WebhookConfig `json:"webhooks,omitempty"`
+
+	// Printers are local slicer apps and print folders exports can be
+	// handed to (see print.go); PrintExports names the one that downloaded
+	// exports go to automatically
+	Printers     map[string]PrinterConfig `json:"printers,omitempty"`
+	PrintExports string                   `json:"printExports,omitempty"`
+}
+
+const defaultProfileName = "default"
+
+// noNetworkVolume as a profile's networkVolumeId launches without a volume
+const noNetworkVolume = "none"
+
+// Profile selects what gets launched. Empty fields use the built-in
+// configuration from main.go.
+type Profile struct {
+	Name            string   `json:"-"`
+	TemplateID      string   `json:"templateId,omitempty"`
+	NetworkVolumeID string   `json:"networkVolumeId,omitempty"`
+	GPUTypes        []string `json:"gpuTypes,omitempty"`
+
+	// NetworkVolume picks the volume by name instead of networkVolumeId;
+	// it is looked up when launching (see volume.go)
+	NetworkVolume string `json:"networkVolume,omitempty"`
+
+	// DataCenters are tried in order when the GPU queue is stuck (e.g.
+	// "EU-RO-1"). Empty lets RunPod pick; a network volume pins its own.
+	DataCenters []string `json:"dataCenters,omitempty"`
+
+	// Spot launches interruptible pods at BidPerGPU USD/hr (0 = the current
+	// minimum bid); OnInterrupt says what happens when RunPod takes one back
+	Spot        bool    `json:"spot,omitempty"`
+	BidPerGPU   float64 `json:"bidPerGpu,omitempty"`
+	OnInterrupt string  `json:"onInterrupt,omitempty"`
+
+	// Account binds the profile to a saved RunPod account (see
+	// accounts.go); launches need a balance for MinHours of the most
+	// expensive GPU type (0 = 1 hour, negative = no check)
+	Account  string  `json:"account,omitempty"`
+	MinHours float64 `json:"minHours,omitempty"`
+}
+
+// profile returns the named profile with defaults filled in
+func (s *Settings) profile(name string) (Profile, error) {
+	p, ok := s.Profiles[name]
+	if !ok && name != defaultProfileName {
+		return Profile{}, fmt.Errorf("unknown profile %q (add it to %s)", name, settingsFileName)
+	}
+	p.Name = name
+	if p.TemplateID == "" {
+		p.TemplateID = templateID
+	}
+	switch {
+	case p.NetworkVolume != "":
+	case p.NetworkVolumeID == "":
+		p.NetworkVolumeID = networkVolumeID
+	case p.NetworkVolumeID == noNetworkVolume:
+		p.NetworkVolumeID = ""
+	}
+	if len(p.GPUTypes) == 0 {
+		p.GPUTypes = gpuTypes
+	}
+	if err := validOnInterrupt(p.OnInterrupt); err != nil {
+		return Profile{}, fmt.Errorf("profile %q: %w", name, err)
+	}
+	return p, nil
+}
+
+// userName returns who is launching, for webhooks
+func (s *Settings) userName() string {
+	if s.User != "" {
+		return s.User
+	}
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return "unknown"
+}
+
+// addSettingsSecrets keeps webhook secrets and URLs (a Slack URL is a
+// credential) out of logs and support bundles
+func (s *Settings) addSettingsSecrets() {
+	for _, hook := range s.Webhooks {
+		addSecret(hook.URL)
+		addSecret(hook.Secret)
+	}
+}
+
+// appSettings is loaded once at startup
+var appSettings = defaultSettings()
+
+func defaultSettings() *Settings {
+	return &Settings{
+		Notifications: map[string]bool{
+			notifyReady:            true,
+			notifyFileBrowserReady: true,
+			notifyBudget80:         true,
+			notifyBudget100:        true,
+			notifyIdle:             true,
+			notifyTerminated:       true,
+			notifyTerminateFailed:  true,
+			notifyExportDownloaded: true,
+			notifyInterrupted:      true,
+		},
+		IdleMinutes: 30,
+	}
+}
+
+// notifies reports whether a desktop notification is enabled
+func (s *Settings) notifies(name string) bool {
+	enabled, ok := s.Notifications[name]
+	return !ok || enabled
+}
+
+// loadSettings reads settings.json, writing the defaults if it doesn't exist
+func loadSettings() (*Settings, error) {
+	dir, err := getStateDir()
+	if err != nil {
+		return defaultSettings(), err
+	}
+	path := filepath.Join(dir, settingsFileName)
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		s := defaultSettings()
+		return s, saveSettings(s)
+	}
+	if err != nil {
+		return defaultSettings(), fmt.Errorf("could not read settings: %w", err)
+	}
+
+	s := defaultSettings()
+	if err := json.Unmarshal(data, s); err != nil {
+		return defaultSettings(), fmt.Errorf("could not parse %s: %w", path, err)
+	}
+	return s, nil
+}
+
+func saveSettings(s *Settings) error {
+	dir, err := getStateDir()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not encode settings: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, settingsFileName), append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("could not write settings: %w", err)
+	}
+	return nil
+}
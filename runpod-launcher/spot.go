@@ -0,0 +1,283 @@
+// Copyright (c) 2025-2026 Mik Gangal
+// Licensed under CC BY-NC-SA 4.0 - https://creativecommons.org/licenses/by-nc-sa/4.0/
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Spot (interruptible) pods cost a fraction of on-demand ones, but RunPod
+// takes them back when it is outbid or needs the capacity. The pod then
+// shows up as EXITED. The launcher watches for that while it starts and
+// while the session runs, and either ends the session or relaunches as the
+// profile's onInterrupt says.
+
+// What to do when a spot pod is interrupted
+const (
+	onInterruptEnd      = "end"       // end the session (default)
+	onInterruptOnDemand = "on-demand" // relaunch as an on-demand pod
+	onInterruptNextGPU  = "next-gpu"  // relaunch as spot on the next GPU type
+)
+
+const interruptCheck = 30 * time.Second
+
+// interruptedError means RunPod took the pod back
+type interruptedError struct {
+	podID string
+	phase string // what the launcher was waiting for, "" once running
+}
+
+func (e *interruptedError) Error() string {
+	if e.phase == "" {
+		return fmt.Sprintf("pod %s was interrupted by RunPod", e.podID)
+	}
+	return fmt.Sprintf("pod %s was interrupted by RunPod while %q", e.podID, e.phase)
+}
+
+// podInterrupted tells the front ends what happened to the session
+func podInterrupted(err *interruptedError) {
+	emit(Event{Type: evInterrupted, PodID: err.podID, Level: levelWarn, ElapsedSec: sessionElapsed(),
+		Message: fmt.Sprintf("%s (outbid, or the capacity was reclaimed). Anything not on the network volume is gone.", capitalize(err.Error()))})
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// validOnInterrupt checks a profile's or flag's onInterrupt value
+func validOnInterrupt(v string) error {
+	switch v {
+	case "", onInterruptEnd, onInterruptOnDemand, onInterruptNextGPU:
+		return nil
+	}
+	return fmt.Errorf("onInterrupt must be %q, %q or %q", onInterruptEnd, onInterruptOnDemand, onInterruptNextGPU)
+}
+
+// interrupted moves on after a spot pod was taken back and describes the
+// decision. gpu and dataCenter are where the interrupted pod ran.
+func (lp *launchPlan) interrupted(gpu, dataCenter string) (string, bool) {
+	if !lp.profile.Spot {
+		return "", false
+	}
+	switch lp.profile.OnInterrupt {
+	case onInterruptOnDemand:
+		lp.profile.Spot = false
+		lp.gpuTypes, lp.dataCenters = lp.profile.GPUTypes, lp.profile.DataCenters
+		return "Relaunching as an on-demand pod", true
+	case onInterruptNextGPU:
+		// The same way a stuck queue moves on: the next GPU type, then data center
+		dataCenters := len(lp.dataCenters)
+		if _, ok := lp.next(&stuckError{phase: phaseWaitingForGPU}, gpu, dataCenter); !ok {
+			return "", false
+		}
+		where := strings.Join(lp.gpuTypes, ", ")
+		if len(lp.dataCenters) != dataCenters {
+			where += " in " + strings.Join(lp.dataCenters, ", ")
+		}
+		return "Relaunching as spot on " + where, true
+	}
+	return "", false
+}
+
+// launchSpotPod rents an interruptible pod through GraphQL, which the REST
+// API can't. GraphQL takes one GPU type, so they are tried in order.
+func launchSpotPod(apiKey, name string, profile Profile, env map[string]string) (*PodResponse, error) {
+	type envVar struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
+	}
+	var vars []envVar
+	for k, v := range env {
+		vars = append(vars, envVar{k, v})
+	}
+
+	var errs []string
+	for _, gpu := range profile.GPUTypes {
+		bid := profile.BidPerGPU
+		if bid <= 0 {
+			var err error
+			if bid, err = minimumBid(apiKey, gpu); err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", gpu, err))
+				continue
+			}
+		}
+
+		input := map[string]interface{}{
+			"name":       name,
+			"templateId": profile.TemplateID,
+			"gpuTypeId":  gpu,
+			"gpuCount":   1,
+			"bidPerGpu":  bid,
+			"cloudType":  "SECURE",
+			"env":        vars,
+		}
+		if profile.NetworkVolumeID != "" {
+			input["networkVolumeId"] = profile.NetworkVolumeID
+		}
+		if len(profile.DataCenters) > 0 {
+			input["dataCenterId"] = profile.DataCenters[0]
+		}
+
+		var result struct {
+			Data struct {
+				Pod *struct {
+					ID            string  `json:"id"`
+					DesiredStatus string  `json:"desiredStatus"`
+					ImageName     string  `json:"imageName"`
+					CostPerHr     float64 `json:"costPerHr"`
+					Machine       *struct {
+						GpuDisplayName string `json:"gpuDisplayName"`
+					} `json:"machine"`
+				} `json:"podRentInterruptable"`
+			} `json:"data"`
+		}
+		err := runpodGraphQL(apiKey,
+			`mutation($input: PodRentInterruptableInput!) { podRentInterruptable(input: $input) { id desiredStatus imageName costPerHr machine { gpuDisplayName } } }`,
+			map[string]interface{}{"input": input}, &result)
+		if err == nil && (result.Data.Pod == nil || result.Data.Pod.ID == "") {
+			err = fmt.Errorf("no pod in response")
+		}
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s at $%.3f/hr: %v", gpu, bid, err))
+			continue
+		}
+
+		p := result.Data.Pod
+		pod := &PodResponse{ID: p.ID, Name: name, DesiredStatus: p.DesiredStatus, ImageName: p.ImageName, CostPerHr: p.CostPerHr}
+		if p.Machine != nil {
+			pod.Machine.GpuDisplayName = p.Machine.GpuDisplayName
+		}
+		if pod.Machine.GpuDisplayName == "" {
+			pod.Machine.GpuDisplayName = gpu
+		}
+		if len(profile.DataCenters) > 0 {
+			pod.Machine.DataCenterID = profile.DataCenters[0]
+		}
+		if pod.CostPerHr == 0 {
+			pod.CostPerHr = bid
+		}
+		return pod, nil
+	}
+	return nil, fmt.Errorf("no spot pod available (%s)", strings.Join(errs, "; "))
+}
+
+// minimumBid returns the lowest spot bid RunPod currently accepts for a GPU type
+func minimumBid(apiKey, gpuType string) (float64, error) {
+	var result struct {
+		Data struct {
+			GpuTypes []struct {
+				LowestPrice struct {
+					MinimumBidPrice float64 `json:"minimumBidPrice"`
+				} `json:"lowestPrice"`
+			} `json:"gpuTypes"`
+		} `json:"data"`
+	}
+	err := runpodGraphQL(apiKey,
+		`query($id: String) { gpuTypes(input: {id: $id}) { lowestPrice(input: {gpuCount: 1}) { minimumBidPrice } } }`,
+		map[string]interface{}{"id": gpuType}, &result)
+	if err != nil {
+		return 0, err
+	}
+	if len(result.Data.GpuTypes) == 0 || result.Data.GpuTypes[0].LowestPrice.MinimumBidPrice <= 0 {
+		return 0, fmt.Errorf("no spot capacity")
+	}
+	return result.Data.GpuTypes[0].LowestPrice.MinimumBidPrice, nil
+}
+
+// runpodGraphQL sends a query with variables and decodes the response into out
+func runpodGraphQL(apiKey, query string, variables map[string]interface{}, out interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	if err != nil {
+		return fmt.Errorf("could not create request body: %w", err)
+	}
+	req, err := http.NewRequest("POST", runpodGraphQLURL+"?api_key="+apiKey, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("could not create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := newHTTPClient(30 * time.Second).Do(req)
+	if err != nil {
+		// The key is part of the URL in the error
+		return fmt.Errorf("API request failed: %s", redact(err.Error()))
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("could not read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API error (%d): %s", resp.StatusCode, string(data))
+	}
+
+	var errResp struct {
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if json.Unmarshal(data, &errResp) == nil && len(errResp.Errors) > 0 {
+		return fmt.Errorf("API error: %s", errResp.Errors[0].Message)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("could not parse response: %w", err)
+	}
+	return nil
+}
+
+// wasInterrupted reports whether the session ended because RunPod took
+// the spot pod back
+func (s *session) wasInterrupted() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.interrupted
+}
+
+// watchInterruption ends the session when RunPod takes the spot pod back.
+// A pod the user stopped is EXITED too, so stopped sessions are skipped.
+// A pod that is gone altogether was terminated, not interrupted.
+func (s *session) watchInterruption(stop <-chan struct{}) {
+	go func() {
+		ticker := time.NewTicker(interruptCheck)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if s.isStopped() {
+					continue
+				}
+				status, err := getPodStatus(s.apiKey, s.podID)
+				if err != nil {
+					continue
+				}
+				if status == "" {
+					// Terminated elsewhere (console, heartbeat timeout):
+					// nothing to relaunch, just end the session
+					logEvent(levelWarn, "Pod %s no longer exists - ending the session", s.podID)
+					s.requestQuit()
+					return
+				}
+				if status != "EXITED" {
+					continue
+				}
+				s.mu.Lock()
+				s.interrupted = true
+				s.mu.Unlock()
+				podInterrupted(&interruptedError{podID: s.podID})
+				s.requestQuit()
+				return
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
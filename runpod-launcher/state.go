@@ -0,0 +1,187 @@
+// Copyright (c) 2025-2026 Mik Gangal
+// Licensed under CC BY-NC-SA 4.0 - https://creativecommons.org/licenses/by-nc-sa/4.0/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	stateDirName  = ".slicer-launcher"
+	stateFileName = "state.json"
+)
+
+// Pod record statuses
+const (
+	podStatusActive      = "active"
+	podStatusUnconfirmed = "termination-unconfirmed"
+	podStatusStopped     = "stopped"
+)
+
+// PodRecord tracks a pod created by this launcher
+type PodRecord struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"createdAt"`
+	Status    string    `json:"status"`
+	Account   string    `json:"account,omitempty"` // saved account the pod runs on ("" = the launching key's)
+}
+
+// LauncherState is persisted between runs so leaked pods can be cleaned up
+type LauncherState struct {
+	Pods []PodRecord `json:"pods"`
+}
+
+var stateMu sync.Mutex
+
+// getStateDir returns ~/.slicer-launcher, creating it if needed
+func getStateDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("could not get home directory: %w", err)
+	}
+	dir := filepath.Join(home, stateDirName)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("could not create state directory: %w", err)
+	}
+	return dir, nil
+}
+
+func loadState() (*LauncherState, error) {
+	dir, err := getStateDir()
+	if err != nil {
+		return nil, err
+	}
+
+	state := &LauncherState{}
+	data, err := os.ReadFile(filepath.Join(dir, stateFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, fmt.Errorf("could not read state file: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("could not parse state file: %w", err)
+	}
+	return state, nil
+}
+
+func saveState(state *LauncherState) error {
+	dir, err := getStateDir()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not encode state: %w", err)
+	}
+
+	// Write to a temp file first so a crash never leaves a truncated state file
+	tmpPath := filepath.Join(dir, stateFileName+".tmp")
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("could not write state file: %w", err)
+	}
+	return os.Rename(tmpPath, filepath.Join(dir, stateFileName))
+}
+
+// updateState loads the state, applies fn and saves the result
+func updateState(fn func(state *LauncherState)) error {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	state, err := loadState()
+	if err != nil {
+		return err
+	}
+	fn(state)
+	return saveState(state)
+}
+
+// recordPod adds or replaces the record for a pod
+func recordPod(rec PodRecord) error {
+	return updateState(func(state *LauncherState) {
+		for i := range state.Pods {
+			if state.Pods[i].ID == rec.ID {
+				state.Pods[i] = rec
+				return
+			}
+		}
+		state.Pods = append(state.Pods, rec)
+	})
+}
+
+func setPodStatus(podID, status string) error {
+	return updateState(func(state *LauncherState) {
+		for i := range state.Pods {
+			if state.Pods[i].ID == podID {
+				state.Pods[i].Status = status
+				return
+			}
+		}
+		state.Pods = append(state.Pods, PodRecord{ID: podID, CreatedAt: time.Now(), Status: status})
+	})
+}
+
+// recordUnconfirmed marks a pod whose termination could not be confirmed,
+// keeping the account it was created on so a later run asks the right one
+func recordUnconfirmed(podID, account string) error {
+	return updateState(func(state *LauncherState) {
+		for i := range state.Pods {
+			if state.Pods[i].ID == podID {
+				state.Pods[i].Status = podStatusUnconfirmed
+				if state.Pods[i].Account == "" {
+					state.Pods[i].Account = account
+				}
+				return
+			}
+		}
+		state.Pods = append(state.Pods, PodRecord{ID: podID, CreatedAt: time.Now(), Status: podStatusUnconfirmed, Account: account})
+	})
+}
+
+// forgetPod removes a pod once its termination has been confirmed
+func forgetPod(podID string) error {
+	return updateState(func(state *LauncherState) {
+		pods := state.Pods[:0]
+		for _, p := range state.Pods {
+			if p.ID != podID {
+				pods = append(pods, p)
+			}
+		}
+		state.Pods = pods
+	})
+}
+
+// cleanupUnconfirmedPods retries termination of pods that a previous run
+// could not confirm as gone, each with the key of the account it runs on.
+// Another account's key gets a 404 and would take the pod for gone.
+func cleanupUnconfirmedPods(apiKey string) {
+	state, err := loadState()
+	if err != nil {
+		logEvent(levelWarn, "Could not read launcher state: %v", err)
+		return
+	}
+
+	for _, p := range state.Pods {
+		if p.Status != podStatusUnconfirmed {
+			continue
+		}
+		logEvent(levelWarn, "Pod %s from a previous session was never confirmed terminated", p.ID)
+		key, err := accountAPIKey(p.Account, apiKey)
+		if err != nil {
+			logEvent(levelWarn, "Pod %s: %v", p.ID, err)
+			continue
+		}
+		if err := terminatePod(key, p.ID); err != nil {
+			reportTerminationFailure(key, p.ID, err)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2025-2026 Mik Gangal
+// Licensed under CC BY-NC-SA 4.0 - https://creativecommons.org/licenses/by-nc-sa/4.0/
+
+package main
+
+import (
+	"archive/zip"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// bundleFiles are the files from the state directory that go into a support
+// bundle. The API key lives elsewhere and is never included.
+var bundleFiles = []string{
+	stateFileName,
+	settingsFileName,
+	historyFileName,
+	"watchdog.log",
+}
+
+// runSupportBundle is the entry point of the "support-bundle" subcommand
+func runSupportBundle(args []string) int {
+	fs := flag.NewFlagSet("support-bundle", flag.ContinueOnError)
+	out := fs.String("o", "", "output zip (default: slicer-support-<time>.zip in the current directory)")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if *out == "" {
+		*out = "slicer-support-" + time.Now().Format("20060102-150405") + ".zip"
+	}
+
+	// Whatever slipped into a log, the key must not end up in the bundle
+	hasKey := false
+	if creds, err := loadCredentials(); err == nil {
+		creds.addSecrets()
+		hasKey = len(creds.Accounts) > 0
+	}
+	addSecret(os.Getenv(apiKeyEnv))
+	if settings, err := loadSettings(); err == nil {
+		settings.addSettingsSecrets()
+	}
+
+	if err := writeSupportBundle(*out, hasKey); err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitError
+	}
+	fmt.Printf("%s✓%s Support bundle written to %s\n", colorGreen, colorReset, *out)
+	fmt.Println("  It contains session logs, launcher state and system info - no API key.")
+	return exitOK
+}
+
+func writeSupportBundle(path string, hasSavedKey bool) error {
+	dir, err := getStateDir()
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("could not create bundle: %w", err)
+	}
+	defer f.Close()
+	zw := zip.NewWriter(f)
+
+	add := func(name string, data []byte) error {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: time.Now()})
+		if err != nil {
+			return fmt.Errorf("could not add %s: %w", name, err)
+		}
+		_, err = w.Write([]byte(redact(string(data))))
+		return err
+	}
+
+	if err := add("environment.txt", []byte(environmentInfo(dir, hasSavedKey))); err != nil {
+		return err
+	}
+	for _, name := range bundleFiles {
+		if data, err := os.ReadFile(filepath.Join(dir, name)); err == nil {
+			if err := add(name, data); err != nil {
+				return err
+			}
+		}
+	}
+	logs, _ := filepath.Glob(filepath.Join(dir, logDirName, "session-*"))
+	for _, p := range logs {
+		if data, err := os.ReadFile(p); err == nil {
+			if err := add(logDirName+"/"+filepath.Base(p), data); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("could not write bundle: %w", err)
+	}
+	return f.Close()
+}
+
+// environmentInfo describes the machine and launcher configuration
+func environmentInfo(stateDir string, hasSavedKey bool) string {
+	var b strings.Builder
+	line := func(format string, a ...interface{}) {
+		fmt.Fprintf(&b, format+"\n", a...)
+	}
+	exe, _ := os.Executable()
+	isSet := func(name string) string {
+		if os.Getenv(name) != "" {
+			return "set"
+		}
+		return "not set"
+	}
+
+	line("Created:     %s", time.Now().Format(time.RFC3339))
+	line("OS/Arch:     %s/%s", runtime.GOOS, runtime.GOARCH)
+	line("Go:          %s", runtime.Version())
+	line("Executable:  %s", exe)
+	line("State dir:   %s", stateDir)
+	line("Template:    %s", templateID)
+	line("Volume:      %s", networkVolumeID)
+	line("GPU types:   %s", strings.Join(gpuTypes, ", "))
+	line("Saved key:   %v", hasSavedKey)
+	line("%s: %s", apiKeyEnv, isSet(apiKeyEnv))
+	line("TERM:        %s", os.Getenv("TERM"))
+	line("LANG:        %s", os.Getenv("LANG"))
+	return b.String()
+}
@@ -0,0 +1,23 @@
+//go:build !linux && !darwin && !windows
+
+// Copyright (c) 2025-2026 Mik Gangal
+// Licensed under CC BY-NC-SA 4.0 - https://creativecommons.org/licenses/by-nc-sa/4.0/
+
+package main
+
+import (
+	"errors"
+	"os"
+)
+
+var errNoTerminal = errors.New("terminal control not supported on this platform")
+
+func makeRaw(f *os.File) (func(), error) {
+	return nil, errNoTerminal
+}
+
+func terminalSize(f *os.File) (int, int, error) {
+	return 0, 0, errNoTerminal
+}
+
+func notifyResize(ch chan<- os.Signal) {}
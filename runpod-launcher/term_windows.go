@@ -0,0 +1,60 @@
+//go:build windows
+
+// Copyright (c) 2025-2026 Mik Gangal
+// Licensed under CC BY-NC-SA 4.0 - https://creativecommons.org/licenses/by-nc-sa/4.0/
+
+package main
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// makeRaw disables line buffering and echo on the console input so single
+// key presses can be read. Ctrl+C is still processed as a signal.
+func makeRaw(f *os.File) (func(), error) {
+	const (
+		enableEchoInput            = 0x0004
+		enableLineInput            = 0x0002
+		enableVirtualTerminalInput = 0x0200
+	)
+
+	handle := syscall.Handle(f.Fd())
+	var old uint32
+	if err := syscall.GetConsoleMode(handle, &old); err != nil {
+		return nil, err
+	}
+
+	setConsoleMode := syscall.NewLazyDLL("kernel32.dll").NewProc("SetConsoleMode")
+	raw := old&^(enableEchoInput|enableLineInput) | enableVirtualTerminalInput
+	if r, _, err := setConsoleMode.Call(uintptr(handle), uintptr(raw)); r == 0 {
+		return nil, err
+	}
+
+	return func() {
+		setConsoleMode.Call(uintptr(handle), uintptr(old))
+	}, nil
+}
+
+// terminalSize returns the console window size in columns and rows
+func terminalSize(f *os.File) (int, int, error) {
+	type coord struct{ X, Y int16 }
+	var info struct {
+		Size              coord
+		CursorPosition    coord
+		Attributes        uint16
+		Left, Top         int16
+		Right, Bottom     int16
+		MaximumWindowSize coord
+	}
+
+	getInfo := syscall.NewLazyDLL("kernel32.dll").NewProc("GetConsoleScreenBufferInfo")
+	if r, _, err := getInfo.Call(f.Fd(), uintptr(unsafe.Pointer(&info))); r == 0 {
+		return 0, 0, err
+	}
+	return int(info.Right-info.Left) + 1, int(info.Bottom-info.Top) + 1, nil
+}
+
+// notifyResize is a no-op on Windows; the dashboard re-reads the size on every redraw
+func notifyResize(ch chan<- os.Signal) {}
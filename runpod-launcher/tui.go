@@ -0,0 +1,668 @@
+// Copyright (c) 2025-2026 Mik Gangal
+// Licensed under CC BY-NC-SA 4.0 - https://creativecommons.org/licenses/by-nc-sa/4.0/
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// tuiUI is the full-screen dashboard (--tui). It draws the whole screen from
+// its own model on every change instead of moving the cursor around, so
+// interleaved updates and window resizes can't garble the output.
+
+const (
+	tuiMaxLogs      = 200
+	tuiTwoColumnMin = 100
+)
+
+type tuiPhase struct {
+	name       string
+	start, end time.Time
+}
+
+type tuiLine struct {
+	text  string
+	color string
+}
+
+type tuiUI struct {
+	mu sync.Mutex
+
+	podID      string
+	gpu        string
+	state      string
+	phases     []tuiPhase
+	detail     string
+	desktopURL string
+	filesURL   string
+	ports      map[int]PortInfo
+	balance    float64
+	costPerHr  float64
+	hasBalance bool
+	deadline   time.Time
+	transfers  map[string]*Transfer
+	logs       []tuiLine
+	confirm    byte   // action key waiting for y/n
+	status     string // one-off footer message
+
+	restore     func()
+	unsubscribe func()
+	redraw      chan struct{}
+	quit        chan struct{}
+	keys        chan byte
+	keysOnce    sync.Once
+}
+
+// startTUI switches the terminal to the dashboard and subscribes it to events
+func startTUI() (*tuiUI, error) {
+	if _, _, err := terminalSize(os.Stdout); err != nil {
+		return nil, fmt.Errorf("not a terminal: %w", err)
+	}
+	restoreInput, err := makeRaw(os.Stdin)
+	if err != nil {
+		return nil, fmt.Errorf("could not configure terminal: %w", err)
+	}
+
+	t := &tuiUI{
+		state:     "Launching",
+		transfers: make(map[string]*Transfer),
+		redraw:    make(chan struct{}, 1),
+		quit:      make(chan struct{}),
+	}
+
+	// Alternate screen buffer, hidden cursor
+	fmt.Print("\033[?1049h\033[?25l")
+	var once sync.Once
+	t.restore = func() {
+		once.Do(func() {
+			close(t.quit)
+			fmt.Print("\033[?25h\033[?1049l")
+			restoreInput()
+		})
+	}
+
+	go t.renderLoop()
+	t.unsubscribe = subscribe(t.handle)
+	return t, nil
+}
+
+// close restores the terminal and stops following events
+func (t *tuiUI) close() {
+	t.restore()
+	t.unsubscribe()
+}
+
+func (t *tuiUI) requestRedraw() {
+	select {
+	case t.redraw <- struct{}{}:
+	default:
+	}
+}
+
+func (t *tuiUI) renderLoop() {
+	resize := make(chan os.Signal, 1)
+	notifyResize(resize)
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-t.quit:
+			return
+		case <-ticker.C:
+		case <-resize:
+		case <-t.redraw:
+		}
+		t.draw()
+	}
+}
+
+func (t *tuiUI) addLog(color, format string, a ...interface{}) {
+	line := tuiLine{
+		text:  time.Now().Format("15:04:05") + "  " + strings.TrimSpace(fmt.Sprintf(format, a...)),
+		color: color,
+	}
+	t.logs = append(t.logs, line)
+	if len(t.logs) > tuiMaxLogs {
+		t.logs = t.logs[len(t.logs)-tuiMaxLogs:]
+	}
+}
+
+func (t *tuiUI) handle(ev Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	switch ev.Type {
+	case evLaunching:
+		t.addLog("", "Launching pod (template %s, GPU %s)", ev.Template, ev.GPU)
+
+	case evCreated:
+		t.podID = ev.PodID
+		t.gpu = ev.GPU
+		t.state = "Created"
+		t.addLog(colorGreen, "Pod created: %s", ev.PodID)
+
+	case evProgress:
+		// A new phase closes the previous one
+		if n := len(t.phases); n == 0 || t.phases[n-1].name != ev.Phase || !t.phases[n-1].end.IsZero() {
+			if n > 0 && t.phases[n-1].end.IsZero() {
+				t.phases[n-1].end = ev.Time
+			}
+			t.phases = append(t.phases, tuiPhase{name: ev.Phase, start: ev.Time})
+		}
+		t.state = ev.Phase
+		t.detail = ev.Detail
+
+	case evPhaseDone:
+		if n := len(t.phases); n > 0 && t.phases[n-1].end.IsZero() {
+			t.phases[n-1].name = ev.Phase
+			t.phases[n-1].end = ev.Time
+		} else {
+			t.phases = append(t.phases, tuiPhase{name: ev.Phase, start: ev.Time, end: ev.Time})
+		}
+		t.state = ev.Phase
+		t.detail = ""
+
+	case evReady:
+		t.desktopURL = ev.DesktopURL
+		t.filesURL = ev.FilesURL
+		t.ports = ev.Ports
+		t.state = "Ready"
+		t.addLog(colorGreen, "Ready in %s", formatDuration(ev.Elapsed()))
+
+	case evFileBrowserReady:
+		t.addLog(colorGreen, "File Browser ready")
+
+	case evFileBrowserMissing:
+		t.addLog(colorYellow, "File Browser not detected (may need manual start)")
+
+	case evBalance:
+		t.balance = ev.Balance
+		t.costPerHr = ev.CostPerHr
+		t.hasBalance = true
+
+	case evBudget:
+		if ev.Deadline != nil {
+			t.deadline = *ev.Deadline
+			t.addLog("", "Pod shuts itself down at %s", t.deadline.Format("15:04"))
+		}
+
+	case evBudgetThreshold, evIdle:
+		t.addLog(colorYellow, "%s", ev.Message)
+
+	case evRetry:
+		// The replaced pod's open phase ends here
+		if n := len(t.phases); n > 0 && t.phases[n-1].end.IsZero() {
+			t.phases[n-1].end = ev.Time
+		}
+		t.state = "Retrying"
+		t.detail = ""
+		t.addLog(colorYellow, "%s", ev.Message)
+
+	case evTransfer:
+		tr := *ev.Transfer
+		t.transfers[tr.Direction+" "+tr.Name] = &tr
+		if tr.Error != "" {
+			t.addLog(colorRed, "%s %s failed: %s", tr.Direction, tr.Name, tr.Error)
+		} else if tr.Done {
+			t.addLog(colorGreen, "%s %s complete (%s)", tr.Direction, tr.Name, formatBytes(tr.Bytes))
+		}
+
+	case evLog:
+		color := ""
+		switch ev.Level {
+		case levelOK:
+			color = colorGreen
+		case levelWarn:
+			color = colorYellow
+		case levelError:
+			color = colorRed
+		}
+		t.addLog(color, "%s", ev.Message)
+
+	case evStopped:
+		t.state = "Stopped"
+		t.addLog(colorYellow, "Pod stopped (GPU billing ended, pod kept)")
+
+	case evInterrupted:
+		t.state = "Interrupted"
+		t.addLog(colorRed, "%s", ev.Message)
+
+	case evTerminating:
+		t.state = "Terminating"
+		t.addLog("", "Terminating pod %s...", ev.PodID)
+
+	case evTerminated:
+		t.state = "Terminated"
+		t.addLog(colorGreen, "Pod terminated")
+
+	case evTerminateFailed:
+		t.state = "TERMINATION UNCONFIRMED"
+		t.addLog(colorRed, "Termination could not be confirmed: %s", ev.Message)
+	}
+
+	t.requestRedraw()
+}
+
+// ========== Input ==========
+
+// run reads key presses until the user quits or the session budget runs out.
+// It returns true if the pod should be kept (it was stopped) rather than terminated.
+func (t *tuiUI) run(s *session) bool {
+	// One reader for the dashboard's lifetime: run is called again for a
+	// relaunched spot pod
+	t.keysOnce.Do(func() {
+		t.keys = make(chan byte)
+		go func() {
+			buf := make([]byte, 16)
+			for {
+				n, err := os.Stdin.Read(buf)
+				if err != nil {
+					close(t.keys)
+					return
+				}
+				for _, b := range buf[:n] {
+					t.keys <- b
+				}
+			}
+		}()
+	})
+	keys := t.keys
+
+	for {
+		select {
+		case <-s.expired:
+			return false
+		case <-s.quit:
+			return false
+		case key, ok := <-keys:
+			if !ok {
+				return false
+			}
+			if done, keep := t.handleKey(s, key); done {
+				return keep
+			}
+			t.requestRedraw()
+		}
+	}
+}
+
+func (t *tuiUI) setStatus(msg string) {
+	t.mu.Lock()
+	t.status = msg
+	t.mu.Unlock()
+}
+
+// handleKey runs the action bound to key. done reports that the dashboard should exit.
+func (t *tuiUI) handleKey(s *session, key byte) (done, keep bool) {
+	t.mu.Lock()
+	pending := t.confirm
+	t.confirm = 0
+	t.status = ""
+	t.mu.Unlock()
+
+	if pending != 0 {
+		if key != 'y' && key != 'Y' {
+			t.setStatus("Cancelled")
+			return false, false
+		}
+		switch pending {
+		case 'x':
+			t.setStatus("Stopping pod...")
+			t.requestRedraw()
+			if err := s.stop(); err != nil {
+				logEvent(levelError, "%v", err)
+			}
+		case 't':
+			return true, false
+		}
+		return false, false
+	}
+
+	switch key {
+	case 'd', 'D':
+		s.openDesktop()
+		t.setStatus("Opening desktop in browser")
+	case 'f', 'F':
+		s.openFiles()
+		t.setStatus("Opening File Browser")
+	case 's', 'S':
+		s.copySSH()
+	case 'e', 'E':
+		s.extendBudget(budgetExtension)
+	case 'm', 'M':
+		t.setStatus("Creating 3D models on the pod...")
+		go func() {
+			s.exportModels(defaultExportDir())
+			t.setStatus("")
+			t.requestRedraw()
+		}()
+	case 'x', 'X':
+		if s.isStopped() {
+			t.setStatus("Pod is already stopped")
+			break
+		}
+		t.mu.Lock()
+		t.confirm = 'x'
+		t.mu.Unlock()
+	case 't', 'T':
+		t.mu.Lock()
+		t.confirm = 't'
+		t.mu.Unlock()
+	case 'q', 'Q':
+		// A stopped pod is kept; a running one is terminated as usual
+		if s.isStopped() {
+			return true, true
+		}
+		t.mu.Lock()
+		t.confirm = 't'
+		t.mu.Unlock()
+	}
+	return false, false
+}
+
+// ========== Rendering ==========
+
+var ansiPattern = regexp.MustCompile("\033\\[[0-9;?]*[a-zA-Z]")
+
+// visibleLen returns the printed width of s, ignoring color codes
+func visibleLen(s string) int {
+	return utf8.RuneCountInString(ansiPattern.ReplaceAllString(s, ""))
+}
+
+// fit truncates or pads plain text to exactly width runes
+func fit(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	n := utf8.RuneCountInString(s)
+	if n > width {
+		r := []rune(s)
+		if width == 1 {
+			return "…"
+		}
+		return string(r[:width-1]) + "…"
+	}
+	return s + strings.Repeat(" ", width-n)
+}
+
+// box draws a titled pane of exactly width x height
+func box(title string, lines []tuiLine, width, height int) []string {
+	if height < 2 || width < 4 {
+		return nil
+	}
+	inner := width - 4
+
+	top := "┌─ " + title + " "
+	top += strings.Repeat("─", max(0, width-1-utf8.RuneCountInString(top))) + "┐"
+	out := []string{colorDim + top + colorReset}
+
+	for i := 0; i < height-2; i++ {
+		text := ""
+		color := ""
+		if i < len(lines) {
+			text = lines[i].text
+			color = lines[i].color
+		}
+		row := colorDim + "│ " + colorReset + color + fit(text, inner) + colorReset + colorDim + " │" + colorReset
+		out = append(out, row)
+	}
+
+	out = append(out, colorDim+"└"+strings.Repeat("─", width-2)+"┘"+colorReset)
+	return out
+}
+
+// sideBySide joins two columns of pane rows
+func sideBySide(left, right []string) []string {
+	n := max(len(left), len(right))
+	out := make([]string, 0, n)
+	leftWidth := 0
+	if len(left) > 0 {
+		leftWidth = visibleLen(left[0])
+	}
+	for i := 0; i < n; i++ {
+		l := strings.Repeat(" ", leftWidth)
+		if i < len(left) {
+			l = left[i]
+		}
+		r := ""
+		if i < len(right) {
+			r = right[i]
+		}
+		out = append(out, l+r)
+	}
+	return out
+}
+
+func (t *tuiUI) podLines() []tuiLine {
+	state := t.state
+	if t.detail != "" {
+		state += " (" + t.detail + ")"
+	}
+	stateColor := colorCyan
+	switch t.state {
+	case "Ready", "Terminated":
+		stateColor = colorGreen
+	case "Stopped", "Terminating":
+		stateColor = colorYellow
+	case "TERMINATION UNCONFIRMED":
+		stateColor = colorRed
+	}
+
+	lines := []tuiLine{
+		{text: "Pod:     " + valueOr(t.podID, "-")},
+		{text: "GPU:     " + valueOr(t.gpu, "-")},
+		{text: "State:   " + state, color: stateColor},
+	}
+	if !launchStart.IsZero() {
+		lines = append(lines, tuiLine{text: "Elapsed: " + formatDuration(time.Since(launchStart))})
+	}
+	return lines
+}
+
+func (t *tuiUI) phaseLines() []tuiLine {
+	var lines []tuiLine
+	for _, p := range t.phases {
+		if p.end.IsZero() {
+			lines = append(lines, tuiLine{
+				text:  fmt.Sprintf("%s %-22s %s", spinner[time.Now().Second()%len(spinner)], p.name, formatDuration(time.Since(p.start))),
+				color: colorCyan,
+			})
+		} else {
+			lines = append(lines, tuiLine{
+				text:  fmt.Sprintf("✓ %-22s %s", p.name, formatDuration(p.end.Sub(p.start))),
+				color: colorGreen,
+			})
+		}
+	}
+	if len(lines) == 0 {
+		lines = append(lines, tuiLine{text: "Waiting for pod...", color: colorDim})
+	}
+	return lines
+}
+
+func (t *tuiUI) endpointLines() []tuiLine {
+	if t.desktopURL == "" {
+		return []tuiLine{{text: "Available once the pod is ready", color: colorDim}}
+	}
+	lines := []tuiLine{
+		{text: "Desktop:"},
+		{text: "  " + t.desktopURL, color: colorCyan},
+		{text: "Files:   " + fileBrowserUser + " / " + fileBrowserPassword},
+		{text: "  " + t.filesURL, color: colorCyan},
+	}
+	if port, ok := t.ports[5901]; ok {
+		lines = append(lines, tuiLine{text: fmt.Sprintf("VNC:     %s:%d", port.IP, port.PublicPort)})
+	}
+	if port, ok := t.ports[22]; ok {
+		lines = append(lines, tuiLine{text: fmt.Sprintf("SSH:     ssh root@%s -p %d", port.IP, port.PublicPort)})
+	}
+	return lines
+}
+
+func (t *tuiUI) costLines() []tuiLine {
+	var lines []tuiLine
+	if t.hasBalance {
+		lines = append(lines,
+			tuiLine{text: fmt.Sprintf("Balance: $%.2f", t.balance), color: colorGreen},
+			tuiLine{text: fmt.Sprintf("Cost:    $%.2f/hr", t.costPerHr), color: colorRed},
+		)
+		if !launchStart.IsZero() {
+			spent := t.costPerHr * time.Since(launchStart).Hours()
+			lines = append(lines, tuiLine{text: fmt.Sprintf("Session: ~$%.2f", spent)})
+		}
+		if t.costPerHr > 0 {
+			lines = append(lines, tuiLine{text: fmt.Sprintf("Runway:  ~%.1f hrs", t.balance/t.costPerHr)})
+		}
+	} else {
+		lines = append(lines, tuiLine{text: "Balance not loaded yet", color: colorDim})
+	}
+
+	if !t.deadline.IsZero() {
+		left := time.Until(t.deadline)
+		color := ""
+		if left < 15*time.Minute {
+			color = colorYellow
+		}
+		text := fmt.Sprintf("Budget:  ends %s (%s left)", t.deadline.Format("15:04"), formatDuration(max(left, 0)))
+		if t.costPerHr > 0 && left > 0 {
+			text += fmt.Sprintf(", ~$%.2f", t.costPerHr*left.Hours())
+		}
+		lines = append(lines, tuiLine{text: text, color: color})
+	} else {
+		lines = append(lines, tuiLine{text: "Budget:  no limit", color: colorDim})
+	}
+	return lines
+}
+
+func (t *tuiUI) transferLines() []tuiLine {
+	if len(t.transfers) == 0 {
+		return []tuiLine{{text: "No transfers", color: colorDim}}
+	}
+	keys := make([]string, 0, len(t.transfers))
+	for k := range t.transfers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var lines []tuiLine
+	for _, k := range keys {
+		tr := t.transfers[k]
+		var progress string
+		color := ""
+		switch {
+		case tr.Error != "":
+			progress = "failed: " + tr.Error
+			color = colorRed
+		case tr.Done:
+			progress = "done " + formatBytes(tr.Bytes)
+			color = colorGreen
+		case tr.Total > 0:
+			pct := float64(tr.Bytes) / float64(tr.Total)
+			bar := int(pct * 20)
+			progress = fmt.Sprintf("[%s%s] %3.0f%% of %s",
+				strings.Repeat("█", bar), strings.Repeat("░", 20-bar), pct*100, formatBytes(tr.Total))
+		default:
+			progress = formatBytes(tr.Bytes)
+		}
+		lines = append(lines, tuiLine{text: fmt.Sprintf("%-8s %s  %s", tr.Direction, tr.Name, progress), color: color})
+	}
+	return lines
+}
+
+func (t *tuiUI) footer(width int) string {
+	if t.confirm != 0 {
+		prompt := "Terminate pod and exit?"
+		if t.confirm == 'x' {
+			prompt = "Stop pod? GPU billing ends but the pod is kept."
+		}
+		return colorYellow + fit(" "+prompt+" [y/N]", width) + colorReset
+	}
+	if t.status != "" {
+		return colorCyan + fit(" "+t.status, width) + colorReset
+	}
+	keys := " [d]esktop  [f]iles  [s]sh copy  [m]odels  [e]xtend +1h  [x] stop  [t]erminate  [q]uit"
+	if width < 96 {
+		keys = " d:desktop f:files s:ssh m:models e:+1h x:stop t:terminate q:quit"
+	}
+	return "\033[7m" + fit(keys, width) + colorReset
+}
+
+func (t *tuiUI) draw() {
+	width, height, err := terminalSize(os.Stdout)
+	if err != nil || width < 40 || height < 12 {
+		return
+	}
+
+	t.mu.Lock()
+	screen := t.render(width, height)
+	t.mu.Unlock()
+	fmt.Print(screen)
+}
+
+// render lays out all panes for a width x height screen
+func (t *tuiUI) render(width, height int) string {
+	var rows []string
+	title := fmt.Sprintf(" 3D Slicer RunPod Launcher │ %s │ %s", valueOr(t.podID, "no pod"), t.state)
+	rows = append(rows, "\033[1;7m"+fit(title, width)+colorReset)
+
+	podLines := t.podLines()
+	phaseLines := t.phaseLines()
+	endpointLines := t.endpointLines()
+	costLines := t.costLines()
+	transferLines := t.transferLines()
+
+	if width >= tuiTwoColumnMin {
+		leftW := width / 2
+		rightW := width - leftW
+		topH := max(len(podLines), len(endpointLines)) + 2
+		midH := max(len(phaseLines), len(costLines)) + 2
+		rows = append(rows, sideBySide(box("Pod", podLines, leftW, topH), box("Connection", endpointLines, rightW, topH))...)
+		rows = append(rows, sideBySide(box("Phases", phaseLines, leftW, midH), box("Cost & Balance", costLines, rightW, midH))...)
+	} else {
+		rows = append(rows, box("Pod", podLines, width, len(podLines)+2)...)
+		rows = append(rows, box("Phases", phaseLines, width, len(phaseLines)+2)...)
+		rows = append(rows, box("Connection", endpointLines, width, len(endpointLines)+2)...)
+		rows = append(rows, box("Cost & Balance", costLines, width, len(costLines)+2)...)
+	}
+	rows = append(rows, box("Transfers", transferLines, width, min(len(transferLines), 4)+2)...)
+
+	// Logs get whatever space is left (newest at the bottom)
+	logH := height - len(rows) - 1
+	if logH >= 3 {
+		visible := t.logs
+		if len(visible) > logH-2 {
+			visible = visible[len(visible)-(logH-2):]
+		}
+		rows = append(rows, box("Log", visible, width, logH)...)
+	}
+	if len(rows) > height-1 {
+		rows = rows[:height-1]
+	}
+
+	var b strings.Builder
+	b.WriteString("\033[H")
+	for _, row := range rows {
+		b.WriteString(row)
+		b.WriteString("\033[K\r\n")
+	}
+	b.WriteString("\033[J")
+	b.WriteString("\033[" + fmt.Sprint(height) + ";1H")
+	b.WriteString(t.footer(width))
+	return b.String()
+}
+
+func valueOr(s, fallback string) string {
+	if s == "" {
+		return fallback
+	}
+	return s
+}
@@ -0,0 +1,403 @@
+// Copyright (c) 2025-2026 Mik Gangal
+// Licensed under CC BY-NC-SA 4.0 - https://creativecommons.org/licenses/by-nc-sa/4.0/
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Network volumes keep data between pods. A volume lives in one data
+// center, so every pod that mounts it runs there - which only works while
+// that data center has the profile's GPU types in stock. The volume
+// subcommands show and manage the account's volumes, and profiles can
+// name a volume instead of using its ID.
+
+const runpodVolumesURL = "https://rest.runpod.io/v1/networkvolumes"
+
+// NetworkVolume is a RunPod network volume
+type NetworkVolume struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Size         int    `json:"size"` // GB
+	DataCenterID string `json:"dataCenterId"`
+}
+
+// runpodREST sends a JSON request to the REST API and decodes the response
+// into out (if not nil)
+func runpodREST(apiKey, method, url string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("could not create request body: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return fmt.Errorf("could not create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
+
+	client := newHTTPClient(30 * time.Second)
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("could not read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var errResp ErrorResponse
+		if json.Unmarshal(data, &errResp) == nil && errResp.Error != "" {
+			return fmt.Errorf("API error (%d): %s", resp.StatusCode, errResp.Error)
+		}
+		return fmt.Errorf("API error (%d): %s", resp.StatusCode, string(data))
+	}
+	if out != nil {
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("could not parse response: %w", err)
+		}
+	}
+	return nil
+}
+
+func listVolumes(apiKey string) ([]NetworkVolume, error) {
+	var vols []NetworkVolume
+	if err := runpodREST(apiKey, "GET", runpodVolumesURL, nil, &vols); err != nil {
+		return nil, err
+	}
+	sort.Slice(vols, func(i, j int) bool { return vols[i].Name < vols[j].Name })
+	return vols, nil
+}
+
+// findVolume picks a volume by ID or by name
+func findVolume(vols []NetworkVolume, nameOrID string) (NetworkVolume, error) {
+	var byName []NetworkVolume
+	for _, v := range vols {
+		if v.ID == nameOrID {
+			return v, nil
+		}
+		if v.Name == nameOrID {
+			byName = append(byName, v)
+		}
+	}
+	switch len(byName) {
+	case 0:
+		return NetworkVolume{}, fmt.Errorf("no network volume %q in this account", nameOrID)
+	case 1:
+		return byName[0], nil
+	}
+	return NetworkVolume{}, fmt.Errorf("%d network volumes are named %q - use the ID instead", len(byName), nameOrID)
+}
+
+// gpuStock returns the stock status per GPU type ID in a data center
+// ("High", "Medium", "Low"; missing = none)
+func gpuStock(apiKey, dataCenterID string) (map[string]string, error) {
+	query := `{"query": "query { dataCenters { id gpuAvailability { gpuTypeId stockStatus } } }"}`
+	req, err := http.NewRequest("POST", runpodGraphQLURL+"?api_key="+apiKey, strings.NewReader(query))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := newHTTPClient(10 * time.Second).Do(req)
+	if err != nil {
+		// The key is part of the URL in the error
+		return nil, fmt.Errorf("API request failed: %s", redact(err.Error()))
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	var result struct {
+		Data struct {
+			DataCenters []struct {
+				ID              string `json:"id"`
+				GPUAvailability []struct {
+					GPUTypeID   string `json:"gpuTypeId"`
+					StockStatus string `json:"stockStatus"`
+				} `json:"gpuAvailability"`
+			} `json:"dataCenters"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("could not parse response: %w", err)
+	}
+
+	for _, dc := range result.Data.DataCenters {
+		if dc.ID != dataCenterID {
+			continue
+		}
+		stock := make(map[string]string)
+		for _, g := range dc.GPUAvailability {
+			if g.StockStatus != "" {
+				stock[g.GPUTypeID] = g.StockStatus
+			}
+		}
+		return stock, nil
+	}
+	return nil, fmt.Errorf("unknown data center %s", dataCenterID)
+}
+
+// prepareVolume resolves a volume picked by name in the profile and warns
+// when the volume's data center has none of the profile's GPU types in
+// stock, since the pod would then sit in the GPU queue
+func prepareVolume(apiKey string, p *Profile) error {
+	if p.NetworkVolumeID == "" && p.NetworkVolume == "" {
+		return nil
+	}
+	vols, err := listVolumes(apiKey)
+	if err != nil {
+		if p.NetworkVolume != "" {
+			return fmt.Errorf("looking up network volume %q: %w", p.NetworkVolume, err)
+		}
+		logEvent(levelWarn, "Could not check the network volume: %v", err)
+		return nil
+	}
+	nameOrID := p.NetworkVolumeID
+	if p.NetworkVolume != "" {
+		nameOrID = p.NetworkVolume
+	}
+	vol, err := findVolume(vols, nameOrID)
+	if err != nil {
+		if p.NetworkVolume != "" {
+			return err
+		}
+		logEvent(levelWarn, "%v", err)
+		return nil
+	}
+	p.NetworkVolumeID = vol.ID
+
+	stock, err := gpuStock(apiKey, vol.DataCenterID)
+	if err != nil {
+		slog.Warn("could not check gpu stock", "dataCenter", vol.DataCenterID, "error", err)
+		return nil
+	}
+	for _, t := range p.GPUTypes {
+		if stock[t] != "" {
+			return nil
+		}
+	}
+	logEvent(levelWarn, "%s (network volume %s) has no %s in stock - the pod may wait in the GPU queue. Try another profile or 'slicer-launcher volume info %s'.",
+		vol.DataCenterID, vol.Name, strings.Join(p.GPUTypes, " / "), vol.Name)
+	return nil
+}
+
+func runVolumeCommand(args []string) int {
+	if len(args) == 0 {
+		fmt.Println("usage: slicer-launcher volume list | info <volume> | create -name N -size GB -dc DC | resize <volume> -size GB")
+		fmt.Println("       slicer-launcher volume usage | cleanup [-pod ID]")
+		return exitUsage
+	}
+	apiKey, err := commandSetup()
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitUsage
+	}
+	switch args[0] {
+	case "list":
+		return runVolumeList(apiKey)
+	case "info":
+		return runVolumeInfo(apiKey, args[1:])
+	case "create":
+		return runVolumeCreate(apiKey, args[1:])
+	case "resize":
+		return runVolumeResize(apiKey, args[1:])
+	case "usage":
+		return runVolumeUsage(apiKey, args[1:])
+	case "cleanup":
+		return runVolumeCleanup(apiKey, args[1:])
+	}
+	fmt.Printf("unknown volume command %q\n", args[0])
+	return exitUsage
+}
+
+// volumePods groups the pods by the volume they mount
+func volumePods(pods []PodSummary) map[string][]PodSummary {
+	attached := make(map[string][]PodSummary)
+	for _, p := range pods {
+		if p.NetworkVolumeID != "" {
+			attached[p.NetworkVolumeID] = append(attached[p.NetworkVolumeID], p)
+		}
+	}
+	return attached
+}
+
+func runVolumeList(apiKey string) int {
+	vols, err := listVolumes(apiKey)
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitError
+	}
+	if len(vols) == 0 {
+		fmt.Println("No network volumes. Create one with 'slicer-launcher volume create'.")
+		return exitOK
+	}
+	pods, err := listPods(apiKey)
+	if err != nil {
+		fmt.Printf("%sWarning: could not list pods: %v%s\n", colorYellow, err, colorReset)
+	}
+	attached := volumePods(pods)
+
+	fmt.Printf("%s%-12s %-24s %7s %-12s %4s  %s%s\n", colorDim, "ID", "Name", "Size", "Data center", "Pods", "Used by", colorReset)
+	for _, v := range vols {
+		fmt.Printf("%-12s %-24s %5dGB %-12s %4d  %s\n", v.ID, truncate(v.Name, 24), v.Size, v.DataCenterID,
+			len(attached[v.ID]), strings.Join(volumeProfiles(v), ", "))
+	}
+	return exitOK
+}
+
+// volumeProfiles names the profiles that launch with a volume
+func volumeProfiles(v NetworkVolume) []string {
+	var names []string
+	profiles := map[string]Profile{defaultProfileName: {}}
+	for name, p := range appSettings.Profiles {
+		profiles[name] = p
+	}
+	for name := range profiles {
+		p, err := appSettings.profile(name)
+		if err == nil && (p.NetworkVolumeID == v.ID || (p.NetworkVolume != "" && p.NetworkVolume == v.Name)) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func runVolumeInfo(apiKey string, args []string) int {
+	fs := flag.NewFlagSet("volume info", flag.ContinueOnError)
+	profileName := fs.String("profile", defaultProfileName, "profile whose GPU types are checked for stock")
+	names, err := parseArgs(fs, args)
+	if err != nil {
+		return exitUsage
+	}
+	if len(names) != 1 {
+		fmt.Println("usage: slicer-launcher volume info <name or id> [-profile P]")
+		return exitUsage
+	}
+
+	vols, err := listVolumes(apiKey)
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitError
+	}
+	vol, err := findVolume(vols, names[0])
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitError
+	}
+
+	fmt.Printf("Volume:      %s (%s)\n", vol.Name, vol.ID)
+	fmt.Printf("Size:        %d GB\n", vol.Size)
+	fmt.Printf("Data center: %s\n", vol.DataCenterID)
+	if profiles := volumeProfiles(vol); len(profiles) > 0 {
+		fmt.Printf("Profiles:    %s\n", strings.Join(profiles, ", "))
+	}
+
+	fmt.Println()
+	if pods, err := listPods(apiKey); err != nil {
+		fmt.Printf("%sWarning: could not list pods: %v%s\n", colorYellow, err, colorReset)
+	} else if attached := volumePods(pods)[vol.ID]; len(attached) > 0 {
+		fmt.Println("Attached pods:")
+		printPods(attached)
+	} else {
+		fmt.Println("No pods attached.")
+	}
+
+	profile, err := appSettings.profile(*profileName)
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitUsage
+	}
+	stock, err := gpuStock(apiKey, vol.DataCenterID)
+	if err != nil {
+		fmt.Printf("%sWarning: could not check GPU stock: %v%s\n", colorYellow, err, colorReset)
+		return exitOK
+	}
+	fmt.Printf("\nGPU stock in %s (profile %s):\n", vol.DataCenterID, profile.Name)
+	for _, t := range profile.GPUTypes {
+		if s := stock[t]; s != "" {
+			fmt.Printf("  %s✓%s %-48s %s\n", colorGreen, colorReset, t, s)
+		} else {
+			fmt.Printf("  %s✗%s %-48s none\n", colorRed, colorReset, t)
+		}
+	}
+	return exitOK
+}
+
+func runVolumeCreate(apiKey string, args []string) int {
+	fs := flag.NewFlagSet("volume create", flag.ContinueOnError)
+	name := fs.String("name", "", "volume name")
+	size := fs.Int("size", 100, "size in GB")
+	dataCenter := fs.String("dc", "", "data center, e.g. EU-RO-1")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if *name == "" || *dataCenter == "" || *size < 1 {
+		fmt.Println("usage: slicer-launcher volume create -name N -size GB -dc DATA-CENTER")
+		return exitUsage
+	}
+
+	req := map[string]interface{}{"name": *name, "size": *size, "dataCenterId": *dataCenter}
+	var vol NetworkVolume
+	if err := runpodREST(apiKey, "POST", runpodVolumesURL, req, &vol); err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitError
+	}
+	fmt.Printf("%s✓%s Created %s (%s, %d GB in %s)\n", colorGreen, colorReset, vol.Name, vol.ID, vol.Size, vol.DataCenterID)
+	fmt.Printf("Use it with \"networkVolume\": %q in a profile in %s\n", vol.Name, settingsFileName)
+	return exitOK
+}
+
+func runVolumeResize(apiKey string, args []string) int {
+	fs := flag.NewFlagSet("volume resize", flag.ContinueOnError)
+	size := fs.Int("size", 0, "new size in GB (volumes can only grow)")
+	yes := fs.Bool("yes", false, "resize without asking for confirmation")
+	names, err := parseArgs(fs, args)
+	if err != nil {
+		return exitUsage
+	}
+	if len(names) != 1 || *size < 1 {
+		fmt.Println("usage: slicer-launcher volume resize <name or id> -size GB")
+		return exitUsage
+	}
+
+	vols, err := listVolumes(apiKey)
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitError
+	}
+	vol, err := findVolume(vols, names[0])
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitError
+	}
+	if *size <= vol.Size {
+		fmt.Printf("%s is %d GB already; network volumes can only grow\n", vol.Name, vol.Size)
+		return exitUsage
+	}
+	if !*yes && !confirm(fmt.Sprintf("Grow %s from %d GB to %d GB? Storage is billed per GB. (y/n): ", vol.Name, vol.Size, *size)) {
+		return exitOK
+	}
+
+	if err := runpodREST(apiKey, "PATCH", runpodVolumesURL+"/"+vol.ID, map[string]int{"size": *size}, nil); err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitError
+	}
+	fmt.Printf("%s✓%s %s is now %d GB\n", colorGreen, colorReset, vol.Name, *size)
+	return exitOK
+}
@@ -0,0 +1,367 @@
+// Copyright (c) 2025-2026 Mik Gangal
+// Licensed under CC BY-NC-SA 4.0 - https://creativecommons.org/licenses/by-nc-sa/4.0/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The shared volume fills up with uploaded studies and Slicer's Export_*
+// folders. volume usage walks the pod's filesystem through the File Browser
+// API of a running pod and reports where the space goes; volume cleanup
+// deletes old studies and exports, archiving exports locally first.
+
+const (
+	volumeRoot        = "/workspace"
+	exportPrefix      = "Export_"
+	usageListWorkers  = 8
+	defaultUsageTop   = 15
+	lowSpaceFreeBytes = 5 << 30 // warn below 5 GB free...
+	lowSpaceUsedRatio = 0.9     // ...or above 90% used
+)
+
+// usageRoots are the directories volume usage reports on
+var usageRoots = []string{volumeRoot, transferDir}
+
+// usageNode is a directory with the totals of everything below it
+type usageNode struct {
+	path     string
+	size     int64
+	files    int
+	modified time.Time // newest file below
+	children []*usageNode
+}
+
+// kind tells exports from uploaded studies in the transfer folder
+func (n *usageNode) kind() string {
+	if path.Dir(n.path) != transferDir {
+		return ""
+	}
+	if strings.HasPrefix(path.Base(n.path), exportPrefix) {
+		return "export"
+	}
+	return "study"
+}
+
+// exportOwner matches export folders tagged with a case (or a batch study
+// name): Export_<case>_<timestamp>
+var exportOwner = regexp.MustCompile(`^Export_(.+)_\d{8}_\d{6}$`)
+
+// owner is the case an export belongs to. Nothing on the volume records
+// who uploaded a study, so only tagged exports have one.
+func (n *usageNode) owner() string {
+	if n.kind() != "export" {
+		return ""
+	}
+	if m := exportOwner.FindStringSubmatch(path.Base(n.path)); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// walkUsage lists a directory tree on the pod, a few directories at a time.
+// Directories that can't be listed are counted as empty and reported.
+func walkUsage(fb *fileBrowserClient, dir string) (*usageNode, []error) {
+	var (
+		mu   sync.Mutex
+		errs []error
+		sem  = make(chan struct{}, usageListWorkers)
+	)
+	var walk func(dir string) *usageNode
+	walk = func(dir string) *usageNode {
+		node := &usageNode{path: dir}
+		sem <- struct{}{}
+		items, err := fb.list(dir)
+		<-sem
+		if err != nil {
+			mu.Lock()
+			errs = append(errs, err)
+			mu.Unlock()
+			return node
+		}
+
+		var wg sync.WaitGroup
+		for _, item := range items {
+			if !item.IsDir {
+				node.size += item.Size
+				node.files++
+				if item.ModTime.After(node.modified) {
+					node.modified = item.ModTime
+				}
+				continue
+			}
+			child := &usageNode{}
+			node.children = append(node.children, child)
+			wg.Add(1)
+			go func(p string) {
+				defer wg.Done()
+				*child = *walk(p)
+			}(path.Join(dir, item.Name))
+		}
+		wg.Wait()
+
+		for _, c := range node.children {
+			node.size += c.size
+			node.files += c.files
+			if c.modified.After(node.modified) {
+				node.modified = c.modified
+			}
+		}
+		return node
+	}
+	return walk(dir), errs
+}
+
+// flatten returns the directories down to depth levels below n
+func (n *usageNode) flatten(depth int) []*usageNode {
+	var out []*usageNode
+	for _, c := range n.children {
+		out = append(out, c)
+		if depth > 1 {
+			out = append(out, c.flatten(depth-1)...)
+		}
+	}
+	return out
+}
+
+// diskUsage returns the size and used bytes of the filesystem holding podPath
+func (fb *fileBrowserClient) diskUsage(podPath string) (total, used int64, err error) {
+	var usage struct {
+		Total int64 `json:"total"`
+		Used  int64 `json:"used"`
+	}
+	if err := fb.getJSON("/api/usage"+escapePath(podPath)+"/", &usage); err != nil {
+		return 0, 0, err
+	}
+	return usage.Total, usage.Used, nil
+}
+
+// volumePod picks the running pod whose File Browser is used to look at
+// the volume: the given one, or one of ours that mounts a network volume
+func volumePod(apiKey, podID string) (*fileBrowserClient, PodSummary, error) {
+	pods, err := listPods(apiKey)
+	if err != nil {
+		return nil, PodSummary{}, err
+	}
+	var pick *PodSummary
+	for i, p := range pods {
+		switch {
+		case podID != "" && p.ID == podID:
+		case podID == "" && p.DesiredStatus == "RUNNING" && p.NetworkVolumeID != "" && (pick == nil || (p.Mine && !pick.Mine)):
+		default:
+			continue
+		}
+		pick = &pods[i]
+	}
+	if pick == nil {
+		if podID != "" {
+			return nil, PodSummary{}, fmt.Errorf("no pod %s in this account", podID)
+		}
+		return nil, PodSummary{}, fmt.Errorf("no running pod with a network volume - start a session first (the volume is read through the pod's File Browser)")
+	}
+
+	fb := newFileBrowserClient(pick.ID)
+	if password := fleetPassword(pick.ID); password != "" {
+		fb.password = password
+	}
+	return fb, *pick, nil
+}
+
+// formatAge formats how long ago t was
+func formatAge(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	d := time.Since(t)
+	switch {
+	case d < time.Hour:
+		return "just now"
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+}
+
+func runVolumeUsage(apiKey string, args []string) int {
+	fs := flag.NewFlagSet("volume usage", flag.ContinueOnError)
+	podID := fs.String("pod", "", "running pod to inspect the volume through (default: one of yours with a volume)")
+	top := fs.Int("top", defaultUsageTop, "number of directories to show")
+	depth := fs.Int("depth", 2, "how deep below /workspace and /FILE TRANSFERS to report directories")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	fb, pod, err := volumePod(apiKey, *podID)
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitError
+	}
+	fmt.Printf("Reading through pod %s (%s)...\n", pod.ID, pod.Name)
+
+	if total, used, err := fb.diskUsage(volumeRoot); err != nil {
+		fmt.Printf("%sWarning: could not read free space: %v%s\n", colorYellow, err, colorReset)
+	} else if total > 0 {
+		color := colorGreen
+		if lowSpace(total, used) {
+			color = colorRed
+		}
+		fmt.Printf("\n%s: %s%s of %s used (%.0f%%), %s free%s\n", volumeRoot, color,
+			formatBytes(used), formatBytes(total), float64(used)/float64(total)*100, formatBytes(total-used), colorReset)
+	}
+
+	var dirs []*usageNode
+	for _, root := range usageRoots {
+		node, errs := walkUsage(fb, root)
+		for _, err := range errs {
+			fmt.Printf("%sWarning: %v%s\n", colorYellow, err, colorReset)
+		}
+		fmt.Printf("%s: %s in %d files\n", root, formatBytes(node.size), node.files)
+		dirs = append(dirs, node.flatten(*depth)...)
+	}
+
+	sort.Slice(dirs, func(i, j int) bool { return dirs[i].size > dirs[j].size })
+	if len(dirs) > *top {
+		dirs = dirs[:*top]
+	}
+	fmt.Printf("\n%s%10s %7s  %-10s %-7s %-16s %s%s\n", colorDim, "Size", "Files", "Modified", "Kind", "Case", "Directory", colorReset)
+	for _, d := range dirs {
+		fmt.Printf("%10s %7d  %-10s %-7s %-16s %s\n", formatBytes(d.size), d.files, formatAge(d.modified), d.kind(), truncate(d.owner(), 16), d.path)
+	}
+
+	for _, d := range dirs {
+		if d.kind() != "" {
+			fmt.Printf("\nFree space with 'slicer-launcher volume cleanup -studies-older-than 30 -exports-older-than 14'\n")
+			break
+		}
+	}
+	return exitOK
+}
+
+func lowSpace(total, used int64) bool {
+	return total > 0 && (total-used < lowSpaceFreeBytes || float64(used)/float64(total) > lowSpaceUsedRatio)
+}
+
+// warnLowSpace warns at launch when the network volume is nearly full
+func warnLowSpace(fb *fileBrowserClient) {
+	total, used, err := fb.diskUsage(volumeRoot)
+	if err != nil || !lowSpace(total, used) {
+		return
+	}
+	logEvent(levelWarn, "Network volume is %.0f%% full (%s free) - run 'slicer-launcher volume usage' to see what uses it",
+		float64(used)/float64(total)*100, formatBytes(total-used))
+}
+
+func runVolumeCleanup(apiKey string, args []string) int {
+	fs := flag.NewFlagSet("volume cleanup", flag.ContinueOnError)
+	podID := fs.String("pod", "", "running pod to reach the volume through (default: one of yours with a volume)")
+	studyDays := fs.Int("studies-older-than", 0, "delete uploaded studies not modified for this many days (0 = keep)")
+	exportDays := fs.Int("exports-older-than", 0, "delete Export_* folders not modified for this many days (0 = keep)")
+	archiveDir := fs.String("archive", "volume-archive", "download exports here before deleting them")
+	noArchive := fs.Bool("no-archive", false, "delete exports without downloading them first")
+	yes := fs.Bool("yes", false, "delete without asking for confirmation")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if *studyDays <= 0 && *exportDays <= 0 {
+		fmt.Println("usage: slicer-launcher volume cleanup -studies-older-than DAYS | -exports-older-than DAYS [-archive DIR | -no-archive] [-yes]")
+		return exitUsage
+	}
+
+	fb, pod, err := volumePod(apiKey, *podID)
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitError
+	}
+	fmt.Printf("Reading %s through pod %s (%s)...\n", transferDir, pod.ID, pod.Name)
+	root, errs := walkUsage(fb, transferDir)
+	for _, err := range errs {
+		fmt.Printf("%sWarning: %v%s\n", colorYellow, err, colorReset)
+	}
+
+	var doomed []*usageNode
+	var freed int64
+	for _, d := range root.children {
+		days := *studyDays
+		if d.kind() == "export" {
+			days = *exportDays
+		}
+		if days <= 0 || d.modified.IsZero() || time.Since(d.modified) < time.Duration(days)*24*time.Hour {
+			continue
+		}
+		doomed = append(doomed, d)
+		freed += d.size
+	}
+	if len(doomed) == 0 {
+		fmt.Println("Nothing to clean up.")
+		return exitOK
+	}
+
+	fmt.Printf("\n%s%10s  %-10s %-7s %-16s %s%s\n", colorDim, "Size", "Modified", "Kind", "Case", "Directory", colorReset)
+	for _, d := range doomed {
+		fmt.Printf("%10s  %-10s %-7s %-16s %s\n", formatBytes(d.size), formatAge(d.modified), d.kind(), truncate(d.owner(), 16), d.path)
+	}
+	fmt.Println()
+	archive := !*noArchive && *exportDays > 0
+	if archive {
+		fmt.Printf("Exports are downloaded to %s first.\n", *archiveDir)
+	}
+	if !*yes && !confirm(fmt.Sprintf("Delete %d folders (%s)? (y/n): ", len(doomed), formatBytes(freed))) {
+		return exitOK
+	}
+
+	code := exitOK
+	for _, d := range doomed {
+		if archive && d.kind() == "export" {
+			if err := archiveExport(fb, d.path, filepath.Join(*archiveDir, path.Base(d.path)+".zip")); err != nil {
+				fmt.Printf("  %s✗%s %s kept: %v\n", colorRed, colorReset, d.path, err)
+				code = exitError
+				continue
+			}
+		}
+		if err := fb.remove(d.path); err != nil {
+			fmt.Printf("  %s✗%s %v\n", colorRed, colorReset, err)
+			code = exitError
+			continue
+		}
+		fmt.Printf("  %s✓%s Deleted %s (%s)\n", colorGreen, colorReset, d.path, formatBytes(d.size))
+	}
+	return code
+}
+
+// archiveExport downloads an export folder as the zip archive dest
+func archiveExport(fb *fileBrowserClient, podPath, dest string) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	body, _, err := fb.download(podPath)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	f, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, body); err != nil {
+		f.Close()
+		os.Remove(dest)
+		return fmt.Errorf("download failed: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("  %s✓%s Archived %s to %s\n", colorGreen, colorReset, podPath, dest)
+	return nil
+}
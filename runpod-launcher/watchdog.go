@@ -0,0 +1,163 @@
+// Copyright (c) 2025-2026 Mik Gangal
+// Licensed under CC BY-NC-SA 4.0 - https://creativecommons.org/licenses/by-nc-sa/4.0/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// The watchdog is a detached copy of the launcher ("slicer-launcher watchdog ...")
+// that terminates the pod if the launcher stops sending heartbeats, e.g. because
+// the terminal window was closed without delivering a signal.
+
+const (
+	heartbeatInterval = 15 * time.Second
+	watchdogPoll      = 5 * time.Second
+	defaultDeadman    = 10 * time.Minute
+	minDeadman        = time.Minute
+
+	heartbeatDone = "done"
+
+	// The API key is handed to the watchdog via the environment, not argv,
+	// so it does not show up in process listings
+	watchdogKeyEnv = "SLICER_LAUNCHER_WATCHDOG_KEY"
+)
+
+func heartbeatPath(podID string) (string, error) {
+	dir, err := getStateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "heartbeat-"+podID), nil
+}
+
+// startHeartbeat writes the heartbeat file until stop is closed
+func startHeartbeat(path string, stop <-chan struct{}) {
+	beat := func() {
+		os.WriteFile(path, []byte(fmt.Sprintf("alive %d\n", time.Now().Unix())), 0600)
+	}
+	beat()
+
+	go func() {
+		ticker := time.NewTicker(heartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				beat()
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// finishHeartbeat tells the watchdog the launcher shut down cleanly
+func finishHeartbeat(path string) {
+	os.WriteFile(path, []byte(heartbeatDone+"\n"), 0600)
+}
+
+// spawnWatchdog starts the detached watchdog process for a pod
+func spawnWatchdog(apiKey, podID, heartbeatFile string, deadman time.Duration) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("could not locate launcher executable: %w", err)
+	}
+
+	dir, err := getStateDir()
+	if err != nil {
+		return err
+	}
+	logFile, err := os.OpenFile(filepath.Join(dir, "watchdog.log"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("could not open watchdog log: %w", err)
+	}
+	defer logFile.Close()
+
+	cmd := exec.Command(exe, "watchdog",
+		"--pod", podID,
+		"--heartbeat", heartbeatFile,
+		"--deadman", deadman.String())
+	cmd.Env = append(os.Environ(), watchdogKeyEnv+"="+apiKey)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	cmd.SysProcAttr = detachedProcAttr()
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("could not start watchdog: %w", err)
+	}
+	return cmd.Process.Release()
+}
+
+// runWatchdog is the entry point of the "watchdog" subcommand
+func runWatchdog(args []string) int {
+	fs := flag.NewFlagSet("watchdog", flag.ContinueOnError)
+	podID := fs.String("pod", "", "pod to guard")
+	heartbeatFile := fs.String("heartbeat", "", "heartbeat file written by the launcher")
+	deadman := fs.Duration("deadman", defaultDeadman, "terminate the pod after this long without a heartbeat")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	apiKey := os.Getenv(watchdogKeyEnv)
+	if *podID == "" || *heartbeatFile == "" || apiKey == "" {
+		fmt.Println("watchdog: --pod, --heartbeat and the API key are required")
+		return 2
+	}
+
+	// Termination progress goes to the watchdog log
+	activePodID = *podID
+	subscribe(logSink)
+	settings, _ := loadSettings() // falls back to the defaults
+	subscribe((&desktopNotifier{settings: settings, watchdog: true}).handle)
+
+	logf := func(format string, a ...interface{}) {
+		fmt.Printf("%s [%s] %s\n", time.Now().Format("2006-01-02 15:04:05"), *podID, fmt.Sprintf(format, a...))
+	}
+	logf("watching heartbeat %s (dead-man timer %s)", *heartbeatFile, *deadman)
+
+	for {
+		time.Sleep(watchdogPoll)
+
+		info, err := os.Stat(*heartbeatFile)
+		if err != nil {
+			logf("heartbeat file gone, exiting")
+			return 0
+		}
+		data, _ := os.ReadFile(*heartbeatFile)
+		if strings.TrimSpace(string(data)) == heartbeatDone {
+			logf("launcher shut down cleanly, exiting")
+			os.Remove(*heartbeatFile)
+			return 0
+		}
+
+		silence := time.Since(info.ModTime())
+		if silence < *deadman {
+			continue
+		}
+
+		// Don't act on pods that are already gone
+		if status, err := getPodStatus(apiKey, *podID); err == nil && (status == "" || status == "TERMINATED") {
+			logf("pod already terminated, exiting")
+			os.Remove(*heartbeatFile)
+			forgetPod(*podID)
+			return 0
+		}
+
+		logf("no heartbeat for %s, terminating pod", formatDuration(silence))
+		if err := terminatePod(apiKey, *podID); err != nil {
+			reportTerminationFailure(apiKey, *podID, err)
+			return 1
+		}
+
+		os.Remove(*heartbeatFile)
+		return 0
+	}
+}
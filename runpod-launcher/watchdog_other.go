@@ -0,0 +1,13 @@
+//go:build !windows
+
+// Copyright (c) 2025-2026 Mik Gangal
+// Licensed under CC BY-NC-SA 4.0 - https://creativecommons.org/licenses/by-nc-sa/4.0/
+
+package main
+
+import "syscall"
+
+func detachedProcAttr() *syscall.SysProcAttr {
+	// New session so the watchdog survives the terminal closing
+	return &syscall.SysProcAttr{Setsid: true}
+}
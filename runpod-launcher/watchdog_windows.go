@@ -0,0 +1,20 @@
+//go:build windows
+
+// Copyright (c) 2025-2026 Mik Gangal
+// Licensed under CC BY-NC-SA 4.0 - https://creativecommons.org/licenses/by-nc-sa/4.0/
+
+package main
+
+import "syscall"
+
+func detachedProcAttr() *syscall.SysProcAttr {
+	const (
+		detachedProcess       = 0x00000008
+		createNewProcessGroup = 0x00000200
+	)
+	// Detach from the console so closing the window doesn't kill the watchdog
+	return &syscall.SysProcAttr{
+		CreationFlags: detachedProcess | createNewProcessGroup,
+		HideWindow:    true,
+	}
+}
@@ -0,0 +1,525 @@
+// Copyright (c) 2025-2026 Mik Gangal
+// Licensed under CC BY-NC-SA 4.0 - https://creativecommons.org/licenses/by-nc-sa/4.0/
+
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Webhooks post session events (pod created, ready, budget, idle, terminated)
+// to URLs from settings.json, e.g. a lab Slack channel. Deliveries are queued
+// and retried with backoff; whatever is still undelivered when the launcher
+// exits is saved and sent on the next run.
+
+const (
+	webhookQueueFile   = "webhook-queue.json"
+	webhookMaxAttempts = 6
+	webhookMaxBackoff  = 5 * time.Minute
+	webhookFlushWait   = 10 * time.Second
+
+	// webhookSignatureHeader carries "sha256=<hex HMAC of the body>"
+	webhookSignatureHeader = "X-Slicer-Signature"
+)
+
+// Webhook payload formats
+const (
+	webhookFormatJSON  = "json"
+	webhookFormatSlack = "slack"
+	webhookFormatTeams = "teams"
+)
+
+// WebhookConfig is one outgoing webhook in settings.json
+type WebhookConfig struct {
+	URL    string   `json:"url"`
+	Format string   `json:"format,omitempty"` // json (default), slack or teams
+	Secret string   `json:"secret,omitempty"` // signs the body with HMAC-SHA256
+	Events []string `json:"events,omitempty"` // see webhookEventNames; empty = all
+}
+
+// webhookEventNames maps session events to the names used in webhooks
+var webhookEventNames = map[string]string{
+	evCreated:         "created",
+	evReady:           "ready",
+	evBudgetThreshold: "budget",
+	evIdle:            "idle",
+	evInterrupted:     "interrupted",
+	evTerminated:      "terminated",
+	evTerminateFailed: "terminate_failed",
+}
+
+func (h WebhookConfig) wants(event string) bool {
+	if len(h.Events) == 0 {
+		return true
+	}
+	for _, e := range h.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookPayload is the body of a generic JSON webhook
+type WebhookPayload struct {
+	Event       string    `json:"event"`
+	Time        time.Time `json:"time"`
+	User        string    `json:"user"`
+	Profile     string    `json:"profile"`
+	PodID       string    `json:"podId"`
+	GPU         string    `json:"gpu,omitempty"`
+	CostPerHr   float64   `json:"costPerHr"`
+	SessionCost float64   `json:"sessionCost"`
+	ElapsedSec  float64   `json:"elapsedSec"`
+	Percent     int       `json:"percent,omitempty"`
+	Message     string    `json:"message,omitempty"`
+	DesktopURL  string    `json:"desktopUrl,omitempty"`
+}
+
+// summary is the one-line text used by the chat formats
+func (p WebhookPayload) summary() string {
+	cost := fmt.Sprintf("$%.2f/hr", p.CostPerHr)
+	switch p.Event {
+	case "created":
+		return fmt.Sprintf("%s started a GPU session (%s, profile %s, %s)", p.User, p.GPU, p.Profile, cost)
+	case "ready":
+		return fmt.Sprintf("%s's session is ready after %s (%s)", p.User, formatDuration(time.Duration(p.ElapsedSec)*time.Second), cost)
+	case "terminated":
+		return fmt.Sprintf("%s ended a GPU session after %s - cost $%.2f",
+			p.User, formatDuration(time.Duration(p.ElapsedSec)*time.Second), p.SessionCost)
+	case "terminate_failed":
+		return fmt.Sprintf("Pod %s of %s may still be running: %s", p.PodID, p.User, p.Message)
+	default:
+		return fmt.Sprintf("%s: %s (so far $%.2f at %s)", p.User, p.Message, p.SessionCost, cost)
+	}
+}
+
+// body encodes the payload in the webhook's format
+func (p WebhookPayload) body(format string) ([]byte, error) {
+	switch format {
+	case webhookFormatSlack:
+		return json.Marshal(map[string]string{"text": p.summary()})
+	case webhookFormatTeams:
+		return json.Marshal(map[string]string{
+			"@type":    "MessageCard",
+			"@context": "https://schema.org/extensions",
+			"summary":  p.summary(),
+			"title":    "3D Slicer GPU session - " + p.Event,
+			"text":     fmt.Sprintf("%s<br>Pod %s, profile %s", p.summary(), p.PodID, p.Profile),
+		})
+	case "", webhookFormatJSON:
+		return json.Marshal(p)
+	}
+	return nil, fmt.Errorf("unknown webhook format %q", format)
+}
+
+// signWebhook returns the signature header value for a body
+func signWebhook(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookDelivery is one queued POST
+type webhookDelivery struct {
+	URL         string          `json:"url"`
+	Secret      string          `json:"secret,omitempty"`
+	Event       string          `json:"event"`
+	Body        json.RawMessage `json:"body"`
+	Attempts    int             `json:"attempts"`
+	NextAttempt time.Time       `json:"nextAttempt"`
+}
+
+// webhookSink turns session events into webhook deliveries
+type webhookSink struct {
+	hooks   []WebhookConfig
+	user    string
+	profile string
+	queue   *webhookQueue
+
+	mu         sync.Mutex
+	gpu        string
+	costPerHr  float64
+	desktopURL string
+}
+
+func newWebhookSink(settings *Settings, profile string) *webhookSink {
+	return &webhookSink{
+		hooks:   settings.Webhooks,
+		user:    settings.userName(),
+		profile: profile,
+		queue:   startWebhookQueue(settings.Webhooks),
+	}
+}
+
+func (w *webhookSink) handle(ev Event) {
+	w.mu.Lock()
+	if ev.GPU != "" {
+		w.gpu = ev.GPU
+	}
+	// Balance events carry the whole account's spend, not this pod's rate
+	if ev.Type == evCreated && ev.CostPerHr > 0 {
+		w.costPerHr = ev.CostPerHr
+	}
+	if ev.DesktopURL != "" {
+		w.desktopURL = ev.DesktopURL
+	}
+	payload := WebhookPayload{
+		Time:       ev.Time,
+		User:       w.user,
+		Profile:    w.profile,
+		PodID:      ev.PodID,
+		GPU:        w.gpu,
+		CostPerHr:  w.costPerHr,
+		ElapsedSec: sessionElapsed(),
+		Percent:    ev.Percent,
+		Message:    ev.Message,
+		DesktopURL: w.desktopURL,
+	}
+	w.mu.Unlock()
+
+	name, ok := webhookEventNames[ev.Type]
+	if !ok {
+		return
+	}
+	payload.Event = name
+	payload.SessionCost = payload.CostPerHr * payload.ElapsedSec / 3600
+
+	for _, hook := range w.hooks {
+		if hook.wants(name) {
+			w.send(hook, payload)
+		}
+	}
+}
+
+func (w *webhookSink) send(hook WebhookConfig, payload WebhookPayload) {
+	body, err := payload.body(hook.Format)
+	if err != nil {
+		slog.Warn("webhook skipped", "url", hook.URL, "error", err)
+		return
+	}
+	w.queue.enqueue(&webhookDelivery{URL: hook.URL, Secret: hook.Secret, Event: payload.Event, Body: body})
+}
+
+// webhookQueue delivers webhooks in the background and retries failures
+type webhookQueue struct {
+	mu       sync.Mutex
+	pending  []*webhookDelivery
+	inFlight *webhookDelivery // being posted right now
+	closed   bool             // flushed; nothing new is sent
+	wake     chan struct{}
+	client   *http.Client
+}
+
+// startWebhookQueue starts the delivery loop, picking up deliveries a
+// previous run could not send to webhooks that are still configured
+func startWebhookQueue(hooks []WebhookConfig) *webhookQueue {
+	q := &webhookQueue{
+		wake:   make(chan struct{}, 1),
+		client: newHTTPClient(15 * time.Second),
+	}
+	if saved, err := loadWebhookQueue(); err != nil {
+		slog.Warn("could not load webhook queue", "error", err)
+	} else {
+		configured := make(map[string]bool)
+		for _, h := range hooks {
+			configured[h.URL] = true
+		}
+		for _, d := range saved {
+			if !configured[d.URL] {
+				slog.Info("webhook dropped, no longer configured", "url", d.URL, "event", d.Event)
+				continue
+			}
+			q.pending = append(q.pending, d)
+		}
+	}
+	go q.run()
+	return q
+}
+
+func (q *webhookQueue) enqueue(d *webhookDelivery) {
+	q.mu.Lock()
+	q.pending = append(q.pending, d)
+	q.mu.Unlock()
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (q *webhookQueue) run() {
+	for {
+		q.mu.Lock()
+		if q.closed {
+			q.mu.Unlock()
+			return
+		}
+		var due *webhookDelivery
+		wait := time.Hour
+		for _, d := range q.pending {
+			if until := time.Until(d.NextAttempt); until <= 0 {
+				due = d
+				break
+			} else if until < wait {
+				wait = until
+			}
+		}
+		q.inFlight = due
+		q.mu.Unlock()
+
+		if due == nil {
+			select {
+			case <-q.wake:
+			case <-time.After(wait):
+			}
+			continue
+		}
+
+		err := q.deliver(due)
+		q.mu.Lock()
+		q.inFlight = nil
+		due.Attempts++
+		if err == nil || due.Attempts >= webhookMaxAttempts {
+			q.remove(due)
+		} else {
+			backoff := min(time.Duration(1<<due.Attempts)*time.Second, webhookMaxBackoff)
+			due.NextAttempt = time.Now().Add(backoff)
+		}
+		q.mu.Unlock()
+
+		switch {
+		case err == nil:
+			slog.Info("webhook delivered", "url", due.URL, "event", due.Event)
+		case due.Attempts >= webhookMaxAttempts:
+			logEvent(levelWarn, "Webhook %s gave up after %d attempts: %v", due.URL, due.Attempts, err)
+		default:
+			slog.Warn("webhook failed, will retry", "url", due.URL, "event", due.Event, "attempt", due.Attempts, "error", err)
+		}
+	}
+}
+
+// remove drops a delivery; the caller holds q.mu
+func (q *webhookQueue) remove(d *webhookDelivery) {
+	for i, p := range q.pending {
+		if p == d {
+			q.pending = append(q.pending[:i], q.pending[i+1:]...)
+			return
+		}
+	}
+}
+
+func (q *webhookQueue) deliver(d *webhookDelivery) error {
+	req, err := http.NewRequest("POST", d.URL, bytes.NewReader(d.Body))
+	if err != nil {
+		return fmt.Errorf("could not create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Slicer-Event", d.Event)
+	if d.Secret != "" {
+		req.Header.Set(webhookSignatureHeader, signWebhook(d.Secret, d.Body))
+	}
+
+	resp, err := q.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("status %d: %s", resp.StatusCode, bytes.TrimSpace(data))
+	}
+	return nil
+}
+
+// flush waits a little for pending deliveries and saves the rest for the
+// next run. A delivery still being posted may have arrived, so it is not
+// saved: sending it again could post it twice.
+func (q *webhookQueue) flush(timeout time.Duration) {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		q.mu.Lock()
+		busy := false
+		for _, d := range q.pending {
+			// Deliveries waiting on a long backoff won't finish in time anyway
+			if time.Until(d.NextAttempt) < time.Until(deadline) {
+				busy = true
+				break
+			}
+		}
+		q.mu.Unlock()
+		if !busy {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	var undelivered []*webhookDelivery
+	for _, d := range q.pending {
+		if d == q.inFlight {
+			slog.Warn("webhook still being delivered at exit, not saved", "url", d.URL, "event", d.Event)
+			continue
+		}
+		undelivered = append(undelivered, d)
+	}
+	if err := saveWebhookQueue(undelivered); err != nil {
+		slog.Warn("could not save webhook queue", "error", err)
+	}
+}
+
+func webhookQueuePath() (string, error) {
+	dir, err := getStateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, webhookQueueFile), nil
+}
+
+// loadWebhookQueue reads and removes the saved queue
+func loadWebhookQueue() ([]*webhookDelivery, error) {
+	path, err := webhookQueuePath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	os.Remove(path)
+
+	var pending []*webhookDelivery
+	if err := json.Unmarshal(data, &pending); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", webhookQueueFile, err)
+	}
+	for _, d := range pending {
+		d.NextAttempt = time.Time{}
+	}
+	return pending, nil
+}
+
+func saveWebhookQueue(pending []*webhookDelivery) error {
+	path, err := webhookQueuePath()
+	if err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		os.Remove(path)
+		return nil
+	}
+	data, err := json.MarshalIndent(pending, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// runWebhookCommand is the entry point of the "webhook" subcommand:
+//
+//	webhook test                      send a test event to every configured webhook
+//	webhook listen [-addr] [-secret]  print webhooks received on a local port
+func runWebhookCommand(args []string) int {
+	if len(args) == 0 {
+		fmt.Println("usage: slicer-launcher webhook test | listen [-addr 127.0.0.1:9099] [-secret s]")
+		return exitUsage
+	}
+	switch args[0] {
+	case "test":
+		return runWebhookTest()
+	case "listen":
+		return runWebhookListen(args[1:])
+	}
+	fmt.Printf("unknown webhook command %q\n", args[0])
+	return exitUsage
+}
+
+func runWebhookTest() int {
+	settings, err := loadSettings()
+	if err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitError
+	}
+	if len(settings.Webhooks) == 0 {
+		fmt.Printf("No webhooks configured in %s\n", settingsFileName)
+		return exitUsage
+	}
+
+	payload := WebhookPayload{
+		Event:     "created",
+		Time:      time.Now(),
+		User:      settings.userName(),
+		Profile:   defaultProfileName,
+		PodID:     "test-pod",
+		GPU:       gpuTypes[0],
+		CostPerHr: 1.0,
+		Message:   "Test event from slicer-launcher webhook test",
+	}
+	q := &webhookQueue{client: newHTTPClient(15 * time.Second)}
+
+	code := exitOK
+	for _, hook := range settings.Webhooks {
+		body, err := payload.body(hook.Format)
+		if err == nil {
+			err = q.deliver(&webhookDelivery{URL: hook.URL, Secret: hook.Secret, Event: payload.Event, Body: body})
+		}
+		if err != nil {
+			fmt.Printf("  %s✗%s %s: %v\n", colorRed, colorReset, hook.URL, err)
+			code = exitError
+			continue
+		}
+		fmt.Printf("  %s✓%s %s\n", colorGreen, colorReset, hook.URL)
+	}
+	return code
+}
+
+// runWebhookListen is a local receiver for trying out webhook settings
+func runWebhookListen(args []string) int {
+	fs := flag.NewFlagSet("webhook listen", flag.ContinueOnError)
+	addr := fs.String("addr", "127.0.0.1:9099", "address to listen on")
+	secret := fs.String("secret", "", "verify signatures with this secret")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	handler := func(rw http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		status := "unsigned"
+		if sig := r.Header.Get(webhookSignatureHeader); sig != "" {
+			status = "signature present"
+			if *secret != "" {
+				if hmac.Equal([]byte(sig), []byte(signWebhook(*secret, body))) {
+					status = "signature OK"
+				} else {
+					status = "SIGNATURE MISMATCH"
+				}
+			}
+		}
+		fmt.Printf("%s %s %s (%s)\n  %s\n", time.Now().Format("15:04:05"), r.Method, r.URL.Path, status, body)
+		rw.WriteHeader(http.StatusNoContent)
+	}
+
+	fmt.Printf("Listening on http://%s/ - add it as a webhook url, Ctrl+C to stop\n", *addr)
+	if err := http.ListenAndServe(*addr, http.HandlerFunc(handler)); err != nil {
+		fmt.Printf("%sError: %v%s\n", colorRed, err, colorReset)
+		return exitError
+	}
+	return exitOK
+}
@@ -0,0 +1,321 @@
+// Copyright (c) 2025-2026 Mik Gangal
+// Licensed under CC BY-NC-SA 4.0 - https://creativecommons.org/licenses/by-nc-sa/4.0/
+
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// The web dashboard (--web) is a page on localhost fed by the same events as
+// the console. Events go to the browser as a Server-Sent Events stream; the
+// buttons call small JSON endpoints that act on the session.
+
+//go:embed webui.html
+var webPage []byte
+
+const (
+	maxWebHistory = 500
+	webClientBuf  = 64
+)
+
+type webUI struct {
+	token string
+	url   string
+	srv   *http.Server
+
+	mu           sync.Mutex
+	history      []Event // replayed to clients that connect late
+	lastProgress *Event
+	clients      map[chan Event]struct{}
+	session      *session
+}
+
+// startWebUI serves the dashboard on a random localhost port. Every request
+// must carry the per-run token, so other local pages can't drive the session.
+func startWebUI() (*webUI, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("could not generate dashboard token: %w", err)
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("could not start web dashboard: %w", err)
+	}
+
+	w := &webUI{
+		token:   hex.EncodeToString(buf),
+		clients: make(map[chan Event]struct{}),
+	}
+	w.url = fmt.Sprintf("http://%s/?t=%s", ln.Addr(), w.token)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", w.servePage)
+	mux.HandleFunc("/events", w.serveEvents)
+	mux.HandleFunc("/api/stop", w.post(w.apiStop))
+	mux.HandleFunc("/api/terminate", w.post(w.apiTerminate))
+	mux.HandleFunc("/api/extend", w.post(w.apiExtend))
+	mux.HandleFunc("/api/upload", w.post(w.apiUpload))
+	mux.HandleFunc("/api/exports", w.authorized(w.apiExports))
+	mux.HandleFunc("/api/exports/download", w.authorized(w.apiDownload))
+
+	w.srv = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go w.srv.Serve(ln)
+	return w, nil
+}
+
+// setSession enables the session actions once the pod exists
+func (w *webUI) setSession(s *session) {
+	w.mu.Lock()
+	w.session = s
+	w.mu.Unlock()
+}
+
+func (w *webUI) currentSession() *session {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.session
+}
+
+// close disconnects all browsers and stops the server
+func (w *webUI) close() {
+	w.mu.Lock()
+	for ch := range w.clients {
+		close(ch)
+		delete(w.clients, ch)
+	}
+	w.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	w.srv.Shutdown(ctx)
+}
+
+func (w *webUI) handle(ev Event) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	switch {
+	case ev.Type == evProgress:
+		w.lastProgress = &ev
+	case ev.Transfer != nil && !ev.Transfer.Done && ev.Transfer.Error == "":
+		// Progress ticks are only sent live
+	default:
+		if ev.Type == evPhaseDone || ev.Type == evReady {
+			w.lastProgress = nil
+		}
+		w.history = append(w.history, ev)
+		if len(w.history) > maxWebHistory {
+			w.history = w.history[len(w.history)-maxWebHistory:]
+		}
+	}
+
+	for ch := range w.clients {
+		select {
+		case ch <- ev:
+		default: // slow browser, drop the event rather than block the session
+		}
+	}
+}
+
+func (w *webUI) checkToken(r *http.Request) bool {
+	token := r.Header.Get("X-Token")
+	if token == "" && r.Method == http.MethodGet {
+		token = r.URL.Query().Get("t")
+	}
+	return token == w.token
+}
+
+func (w *webUI) authorized(fn http.HandlerFunc) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		if !w.checkToken(r) {
+			http.Error(rw, "forbidden", http.StatusForbidden)
+			return
+		}
+		fn(rw, r)
+	}
+}
+
+// post wraps a session action: POST only, token in the X-Token header (so
+// plain cross-site form posts are rejected), and only once the pod exists
+func (w *webUI) post(fn func(s *session, rw http.ResponseWriter, r *http.Request)) http.HandlerFunc {
+	return w.authorized(func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s := w.currentSession()
+		if s == nil {
+			http.Error(rw, "the pod is not running yet", http.StatusServiceUnavailable)
+			return
+		}
+		fn(s, rw, r)
+	})
+}
+
+func (w *webUI) servePage(rw http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(rw, r)
+		return
+	}
+	if !w.checkToken(r) {
+		http.Error(rw, "forbidden - open the link printed by the launcher", http.StatusForbidden)
+		return
+	}
+	rw.Header().Set("Content-Type", "text/html; charset=utf-8")
+	rw.Header().Set("Cache-Control", "no-store")
+	rw.Write(webPage)
+}
+
+// serveEvents streams the history and then live events as SSE
+func (w *webUI) serveEvents(rw http.ResponseWriter, r *http.Request) {
+	if !w.checkToken(r) {
+		http.Error(rw, "forbidden", http.StatusForbidden)
+		return
+	}
+	flusher, ok := rw.(http.Flusher)
+	if !ok {
+		http.Error(rw, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	ch := make(chan Event, webClientBuf)
+	w.mu.Lock()
+	backlog := append([]Event{}, w.history...)
+	if w.lastProgress != nil {
+		backlog = append(backlog, *w.lastProgress)
+	}
+	w.clients[ch] = struct{}{}
+	w.mu.Unlock()
+
+	defer func() {
+		w.mu.Lock()
+		if _, ok := w.clients[ch]; ok {
+			delete(w.clients, ch)
+			close(ch)
+		}
+		w.mu.Unlock()
+	}()
+
+	rw.Header().Set("Content-Type", "text/event-stream")
+	rw.Header().Set("Cache-Control", "no-store")
+	send := func(ev Event) bool {
+		data, _ := json.Marshal(ev)
+		_, err := fmt.Fprintf(rw, "data: %s\n\n", data)
+		flusher.Flush()
+		return err == nil
+	}
+
+	for _, ev := range backlog {
+		if !send(ev) {
+			return
+		}
+	}
+	for {
+		select {
+		case ev, ok := <-ch:
+			if !ok || !send(ev) {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func writeJSON(rw http.ResponseWriter, v interface{}) {
+	rw.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(rw).Encode(v)
+}
+
+func (w *webUI) apiStop(s *session, rw http.ResponseWriter, r *http.Request) {
+	if err := s.stop(); err != nil {
+		logEvent(levelError, "%v", err)
+		http.Error(rw, err.Error(), http.StatusBadGateway)
+		return
+	}
+	writeJSON(rw, map[string]bool{"ok": true})
+}
+
+func (w *webUI) apiTerminate(s *session, rw http.ResponseWriter, r *http.Request) {
+	logEvent(levelInfo, "Termination requested from the web dashboard")
+	s.requestQuit()
+	writeJSON(rw, map[string]bool{"ok": true})
+}
+
+func (w *webUI) apiExtend(s *session, rw http.ResponseWriter, r *http.Request) {
+	s.extendBudget(budgetExtension)
+	writeJSON(rw, map[string]bool{"ok": true})
+}
+
+func (w *webUI) apiUpload(s *session, rw http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		http.Error(rw, "missing file name", http.StatusBadRequest)
+		return
+	}
+	if err := s.upload(name, r.Body, r.ContentLength); err != nil {
+		http.Error(rw, err.Error(), http.StatusBadGateway)
+		return
+	}
+	writeJSON(rw, map[string]bool{"ok": true})
+}
+
+func (w *webUI) apiExports(rw http.ResponseWriter, r *http.Request) {
+	s := w.currentSession()
+	if s == nil {
+		writeJSON(rw, []fileBrowserItem{})
+		return
+	}
+	exports, err := s.listExports()
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadGateway)
+		return
+	}
+	if exports == nil {
+		exports = []fileBrowserItem{}
+	}
+	writeJSON(rw, exports)
+}
+
+func (w *webUI) apiDownload(rw http.ResponseWriter, r *http.Request) {
+	s := w.currentSession()
+	if s == nil {
+		http.Error(rw, "the pod is not running yet", http.StatusServiceUnavailable)
+		return
+	}
+	name := r.URL.Query().Get("name")
+	zw := &zipResponse{rw: rw, filename: caseExportName(activeCase, name) + ".zip"}
+	if err := s.downloadExport(name, zw); err != nil {
+		logEvent(levelError, "Download of %s failed: %v", name, err)
+		if !zw.started {
+			http.Error(rw, err.Error(), http.StatusBadGateway)
+		}
+	}
+}
+
+// zipResponse sends the download headers with the first byte, so errors
+// before that can still be reported as an HTTP error
+type zipResponse struct {
+	rw       http.ResponseWriter
+	filename string
+	started  bool
+}
+
+func (z *zipResponse) Write(p []byte) (int, error) {
+	if !z.started {
+		z.started = true
+		z.rw.Header().Set("Content-Type", "application/zip")
+		z.rw.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", z.filename))
+	}
+	return z.rw.Write(p)
+}